@@ -0,0 +1,104 @@
+package ffcmd
+
+import (
+	"fmt"
+)
+
+// BGM represents the background music mixed with the main audio.
+type BGM struct {
+	// File is the music file.
+	File string
+	// Volume is the volume of music relative to the main audio(e.g. 0.3). 0 means no change.
+	Volume float32
+	// FadeIn, FadeOut are the durations of fade-in / fade-out in seconds. 0 means no fade.
+	// Fade-out requires the duration of the main audio.
+	FadeIn  float32
+	FadeOut float32
+	// Loop loops the music if it's shorter than the main audio.
+	Loop bool
+	// Duck lowers the music under the dialogue of the main audio by sidechaincompress filter.
+	Duck bool
+}
+
+// FilterChains adds the music file as input of ffmpeg and returns the filterchains to mix the music with the main audio.
+// ff: ffmpeg command.
+// main, mainID: filterchain of main audio and the 0-based index of its output.
+// duration: duration of the main audio in seconds to trim the music and fade out.
+// 0 means unknown, the music is still cut at the end of the main audio but there's no fade-out.
+// output: output label of the mixed audio in the "[OUTPUT_LABEL]" format.
+// The last filterchain outputs the mixed audio.
+func (b *BGM) FilterChains(ff *FFmpeg, main *FilterChain, mainID int, duration float32, output string) ([]*FilterChain, error) {
+	if b.File == "" {
+		return nil, fmt.Errorf("empty music file")
+	}
+
+	if b.Volume < 0 || b.FadeIn < 0 || b.FadeOut < 0 || duration < 0 {
+		return nil, fmt.Errorf("negative volume, fade or duration")
+	}
+
+	if duration > 0 && b.FadeIn+b.FadeOut > duration {
+		return nil, fmt.Errorf("fade durations exceed the duration")
+	}
+
+	var fcs []*FilterChain
+	name := labelName(output)
+
+	bgm := NewFilterChain(fmt.Sprintf("[%s_music]", name))
+	bgm.AddInputByID(ff.AddInput(b.File), "a", 0)
+
+	if b.Loop {
+		// Loop infinitely and it'll be trimmed.
+		bgm.Chain("aloop=loop=-1:size=2e+09")
+	}
+
+	if duration > 0 {
+		bgm.Chain(fmt.Sprintf("atrim=duration=%s", formatSecond(duration))).Chain("asetpts=PTS-STARTPTS")
+	}
+
+	if b.Volume > 0 {
+		bgm.Chain(fmt.Sprintf("volume=%.3f", b.Volume))
+	}
+
+	if b.FadeIn > 0 {
+		bgm.Chain(fmt.Sprintf("afade=t=in:st=0:d=%s", formatSecond(b.FadeIn)))
+	}
+
+	if b.FadeOut > 0 && duration > 0 {
+		bgm.Chain(fmt.Sprintf("afade=t=out:st=%s:d=%s", formatSecond(duration-b.FadeOut), formatSecond(b.FadeOut)))
+	}
+
+	// Make sure music filterchain has at least one filter to output the label.
+	if len(bgm.filters) == 0 {
+		bgm.Chain("anull")
+	}
+	fcs = append(fcs, bgm)
+
+	music, musicID := bgm, 0
+
+	if b.Duck {
+		// Main audio is used twice: as the sidechain to compress music and to mix.
+		split, err := NewSplitFilterChain(main, mainID, "a", 2)
+		if err != nil {
+			return nil, fmt.Errorf("NewSplitFilterChain() error: %v", err)
+		}
+		fcs = append(fcs, split)
+
+		ducked := NewFilterChain(fmt.Sprintf("[%s_ducked]", name))
+		ducked.AddInputByOutput(bgm, 0)
+		ducked.AddInputByOutput(split, 1)
+		ducked.Chain("sidechaincompress=threshold=0.05:ratio=8:attack=20:release=400")
+		fcs = append(fcs, ducked)
+
+		main, mainID = split, 0
+		music, musicID = ducked, 0
+	}
+
+	mix := NewFilterChain(output)
+	mix.AddInputByOutput(main, mainID)
+	mix.AddInputByOutput(music, musicID)
+	// Keep the duration and volume of main audio.
+	mix.Chain("amix=inputs=2:duration=first:dropout_transition=0:normalize=0")
+	fcs = append(fcs, mix)
+
+	return fcs, nil
+}
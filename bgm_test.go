@@ -0,0 +1,57 @@
+package ffcmd_test
+
+import (
+	"fmt"
+
+	"github.com/northbright/ffcmd"
+)
+
+func ExampleBGM() {
+	tl := ffcmd.NewTimeline(ffcmd.Profile{W: 1280, H: 720, FPS: 30})
+	tl.AddVideoClip(ffcmd.VideoClip{File: "01.mp4", Start: "00:00:02", End: "00:00:12"})
+	tl.AddImageClip(ffcmd.ImageClip{File: "ed.jpg", Duration: 5})
+
+	// Loop the music to the duration of timeline with 30% volume.
+	// Fade in / out the music and lower it under the dialogue.
+	tl.SetBGM(&ffcmd.BGM{
+		File:    "music.mp3",
+		Volume:  0.3,
+		FadeIn:  2,
+		FadeOut: 3,
+		Loop:    true,
+		Duck:    true,
+	})
+
+	ffmpeg, err := tl.FFmpeg("output.mp4", true)
+	if err != nil {
+		fmt.Printf("tl.FFmpeg() error: %v", err)
+		return
+	}
+
+	str, err := ffmpeg.String()
+	if err != nil {
+		fmt.Printf("ffmpeg.String() error: %v", err)
+		return
+	}
+
+	fmt.Println(str)
+
+	// Output:
+	// echo "y" | ffmpeg \
+	// -i "01.mp4" \
+	// -i "ed.jpg" \
+	// -i "music.mp3" \
+	// -filter_complex " \
+	// [0:v:0]trim=start=2.000:end=12.000,setpts=PTS-STARTPTS,scale=1280:720:force_original_aspect_ratio=decrease,pad=1280:720:(ow-iw)/2:(oh-ih)/2,setsar=1:1,fps=30,format=pix_fmts=yuv420p[clip_00_v];
+	// [0:a:0]atrim=start=2.000:end=12.000,asetpts=PTS-STARTPTS,aresample=48000,aformat=sample_fmts=fltp:sample_rates=48000:channel_layouts=stereo[clip_00_a];
+	// [1:v:0]loop=loop=149:size=1,setpts=N/(30*TB),scale=1280:720:force_original_aspect_ratio=decrease,pad=1280:720:(ow-iw)/2:(oh-ih)/2,setsar=1:1,fps=30,format=pix_fmts=yuv420p[clip_01_v];
+	// aevalsrc=0:c=stereo:s=48000:d=5.000,aresample=48000,aformat=sample_fmts=fltp:sample_rates=48000:channel_layouts=stereo[clip_01_a];
+	// [clip_00_v][clip_00_a][clip_01_v][clip_01_a]concat=n=2:v=1:a=1[outv][outa];
+	// [2:a:0]aloop=loop=-1:size=2e+09,atrim=duration=15.000,asetpts=PTS-STARTPTS,volume=0.300,afade=t=in:st=0:d=2.000,afade=t=out:st=12.000:d=3.000[outa_bgm_music];
+	// [outa]asplit=2[outa_0][outa_1];
+	// [outa_bgm_music][outa_1]sidechaincompress=threshold=0.05:ratio=8:attack=20:release=400[outa_bgm_ducked];
+	// [outa_0][outa_bgm_ducked]amix=inputs=2:duration=first:dropout_transition=0:normalize=0[outa_bgm]" \
+	// -map "[outa_bgm]" \
+	// -map "[outv]" \
+	// output.mp4
+}
@@ -0,0 +1,368 @@
+package ffcmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FilterInfo represents a filter supported by ffmpeg.
+type FilterInfo struct {
+	// Name is the name of filter.
+	Name string
+	// Timeline, SliceThreading, Command are the flags of filter.
+	Timeline       bool
+	SliceThreading bool
+	Command        bool
+	// Inputs, Outputs are the types of input / output pads(e.g. "VV" for 2 video inputs, "N" for dynamic, "|" for source / sink).
+	Inputs  string
+	Outputs string
+	// Description is the description of filter.
+	Description string
+}
+
+// CodecInfo represents an encoder or decoder supported by ffmpeg.
+type CodecInfo struct {
+	// Name is the name of encoder / decoder(e.g. "libx264", "aac").
+	Name string
+	// Type is the type of codec: "video", "audio" or "subtitle".
+	Type string
+	// Experimental indicates the codec is experimental.
+	Experimental bool
+	// Description is the description of codec.
+	Description string
+}
+
+// MuxerInfo represents a muxer supported by ffmpeg.
+type MuxerInfo struct {
+	// Name is the name of muxer(e.g. "mp4", "gif").
+	Name string
+	// Description is the description of muxer.
+	Description string
+}
+
+// PixFmtInfo represents a pixel format supported by ffmpeg.
+type PixFmtInfo struct {
+	// Name is the name of pixel format(e.g. "yuv420p").
+	Name string
+	// Input, Output indicate if it's supported as input / output format for conversion.
+	Input  bool
+	Output bool
+	// Components is the number of components.
+	Components int
+	// BitsPerPixel is the bits per pixel.
+	BitsPerPixel int
+}
+
+// Capabilities represents the version, filters, encoders, decoders, muxers and pixel formats supported by a ffmpeg build.
+type Capabilities struct {
+	// Version is the version of ffmpeg(e.g. "6.1.1").
+	Version string
+	// Configuration is the configure options of the build(e.g. "--enable-libass").
+	Configuration []string
+	Filters       map[string]FilterInfo
+	Encoders      map[string]CodecInfo
+	Decoders      map[string]CodecInfo
+	Muxers        map[string]MuxerInfo
+	PixFmts       map[string]PixFmtInfo
+}
+
+var (
+	versionRegexp = regexp.MustCompile(`(?m)^ffmpeg version (\S+)`)
+	configRegexp  = regexp.MustCompile(`(?m)^\s*configuration:(.*)$`)
+	filterRegexp  = regexp.MustCompile(`^\s*([T.])([S.])([C.])\s+(\S+)\s+(\S+)->(\S+)\s+(.*)$`)
+	codecRegexp   = regexp.MustCompile(`^\s*([VAS])([F.])([S.])([X.])([B.])([D.])\s+(\S+)\s+(.*)$`)
+	muxerRegexp   = regexp.MustCompile(`^\s*([D ])E([d ])?\s+(\S+)\s+(.*)$`)
+	pixFmtRegexp  = regexp.MustCompile(`^([I.])([O.])\S{3}\s+(\S+)\s+(\d+)\s+(\d+)`)
+)
+
+// codecTypes maps the type flags of codecs to the types.
+var codecTypes = map[string]string{"V": "video", "A": "audio", "S": "subtitle"}
+
+// parseCodecs parses the output of "ffmpeg -encoders" or "ffmpeg -decoders".
+func parseCodecs(output string) map[string]CodecInfo {
+	codecs := make(map[string]CodecInfo)
+
+	for _, line := range strings.Split(output, "\n") {
+		m := codecRegexp.FindStringSubmatch(line)
+		// Skip the legend(e.g. " V..... = Video").
+		if m == nil || m[7] == "=" {
+			continue
+		}
+
+		codecs[m[7]] = CodecInfo{Name: m[7], Type: codecTypes[m[1]], Experimental: m[4] == "X", Description: strings.TrimSpace(m[8])}
+	}
+
+	return codecs
+}
+
+// ParseCapabilities parses the outputs of ffmpeg with "-hide_banner" and returns the capabilities.
+// version, filters, encoders, decoders, muxers, pixFmts: outputs of "-version", "-filters", "-encoders", "-decoders", "-muxers" and "-pix_fmts".
+func ParseCapabilities(version, filters, encoders, decoders, muxers, pixFmts string) (*Capabilities, error) {
+	caps := &Capabilities{
+		Filters: make(map[string]FilterInfo),
+		Muxers:  make(map[string]MuxerInfo),
+		PixFmts: make(map[string]PixFmtInfo),
+	}
+
+	m := versionRegexp.FindStringSubmatch(version)
+	if m == nil {
+		return nil, fmt.Errorf("no version in the output")
+	}
+	caps.Version = m[1]
+
+	if m = configRegexp.FindStringSubmatch(version); m != nil {
+		caps.Configuration = strings.Fields(m[1])
+	}
+
+	for _, line := range strings.Split(filters, "\n") {
+		m := filterRegexp.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		caps.Filters[m[4]] = FilterInfo{
+			Name:           m[4],
+			Timeline:       m[1] == "T",
+			SliceThreading: m[2] == "S",
+			Command:        m[3] == "C",
+			Inputs:         m[5],
+			Outputs:        m[6],
+			Description:    strings.TrimSpace(m[7]),
+		}
+	}
+
+	caps.Encoders = parseCodecs(encoders)
+	caps.Decoders = parseCodecs(decoders)
+
+	// Muxer list starts after the "--" line.
+	started := false
+	for _, line := range strings.Split(muxers, "\n") {
+		// The legend ends with "--" or "---"(ffmpeg >= 6).
+		if l := strings.TrimSpace(line); l != "" && strings.Trim(l, "-") == "" {
+			started = true
+			continue
+		}
+
+		if !started {
+			continue
+		}
+
+		// ffmpeg >= 6 has the third flag column of devices(e.g. "  E  mp4", "  Ed alsa").
+		m := muxerRegexp.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		// Some muxers have multiple names(e.g. "matroska,webm").
+		for _, name := range strings.Split(m[3], ",") {
+			caps.Muxers[name] = MuxerInfo{Name: name, Description: strings.TrimSpace(m[4])}
+		}
+	}
+
+	for _, line := range strings.Split(pixFmts, "\n") {
+		m := pixFmtRegexp.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		components, _ := strconv.Atoi(m[4])
+		bpp, _ := strconv.Atoi(m[5])
+
+		caps.PixFmts[m[3]] = PixFmtInfo{Name: m[3], Input: m[1] == "I", Output: m[2] == "O", Components: components, BitsPerPixel: bpp}
+	}
+
+	return caps, nil
+}
+
+// HasFilter returns if the filter is supported.
+func (caps *Capabilities) HasFilter(name string) bool {
+	_, ok := caps.Filters[name]
+	return ok
+}
+
+// HasEncoder returns if the encoder is supported.
+func (caps *Capabilities) HasEncoder(name string) bool {
+	_, ok := caps.Encoders[name]
+	return ok
+}
+
+// HasDecoder returns if the decoder is supported.
+func (caps *Capabilities) HasDecoder(name string) bool {
+	_, ok := caps.Decoders[name]
+	return ok
+}
+
+// HasMuxer returns if the muxer is supported.
+func (caps *Capabilities) HasMuxer(name string) bool {
+	_, ok := caps.Muxers[name]
+	return ok
+}
+
+// HasPixFmt returns if the pixel format is supported as output format.
+func (caps *Capabilities) HasPixFmt(name string) bool {
+	pf, ok := caps.PixFmts[name]
+	return ok && pf.Output
+}
+
+var (
+	capsMu    sync.Mutex
+	capsCache = make(map[string]*Capabilities)
+)
+
+// runBinary runs the ffmpeg binary with "-hide_banner" and the arguments and returns the output of stdout.
+func runBinary(ctx context.Context, binary string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, binary, append([]string{"-hide_banner"}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s %s error: %v, stderr: %s", binary, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// DiscoverCapabilities runs the ffmpeg binary to discover the capabilities. The result is cached per binary.
+// ctx: context to cancel ffmpeg.
+// binary: name or path of ffmpeg binary. Empty means "ffmpeg" in PATH.
+func DiscoverCapabilities(ctx context.Context, binary string) (*Capabilities, error) {
+	if binary == "" {
+		binary = "ffmpeg"
+	}
+
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return nil, fmt.Errorf("exec.LookPath() error: %v", err)
+	}
+
+	capsMu.Lock()
+	caps, ok := capsCache[path]
+	capsMu.Unlock()
+
+	if ok {
+		return caps, nil
+	}
+
+	// Run ffmpeg without holding the lock so that discovering a binary does not block the others and cache hits.
+
+	var outputs []string
+	for _, arg := range []string{"-version", "-filters", "-encoders", "-decoders", "-muxers", "-pix_fmts"} {
+		out, err := runBinary(ctx, path, arg)
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, out)
+	}
+
+	caps, err = ParseCapabilities(outputs[0], outputs[1], outputs[2], outputs[3], outputs[4], outputs[5])
+	if err != nil {
+		return nil, err
+	}
+
+	capsMu.Lock()
+	defer capsMu.Unlock()
+
+	// Keep the result stored by another goroutine discovering the same binary at the same time.
+	if cached, ok := capsCache[path]; ok {
+		return cached, nil
+	}
+
+	capsCache[path] = caps
+	return caps, nil
+}
+
+// filterName returns the name of filter without the instance name(e.g. "drawtext" for "drawtext@title=text=Hello").
+func filterName(filter string) string {
+	name, _ := parseFilter(filter)
+	name, _, _ = strings.Cut(name, "@")
+	return name
+}
+
+// isCodecOption returns if it's the option to set codec(e.g. "-c:v", "-acodec").
+func isCodecOption(option string) bool {
+	switch option {
+	case "-c", "-codec", "-vcodec", "-acodec", "-scodec":
+		return true
+	}
+	return strings.HasPrefix(option, "-c:") || strings.HasPrefix(option, "-codec:")
+}
+
+// checkOptions checks if the codecs, muxers and pixel formats in the options are supported.
+// input: if the options are input options. Codecs of input options are decoders and muxers are not checked.
+func (caps *Capabilities) checkOptions(options []string, input bool) []error {
+	var errs []error
+
+	for i := 0; i+1 < len(options); i++ {
+		option, value := options[i], options[i+1]
+
+		switch {
+		case isCodecOption(option):
+			if value == "copy" {
+				continue
+			}
+
+			if input && !caps.HasDecoder(value) {
+				errs = append(errs, fmt.Errorf("decoder %q is not supported", value))
+			} else if !input && !caps.HasEncoder(value) {
+				errs = append(errs, fmt.Errorf("encoder %q is not supported", value))
+			}
+		case option == "-f" && !input:
+			if !caps.HasMuxer(value) {
+				errs = append(errs, fmt.Errorf("muxer %q is not supported", value))
+			}
+		case option == "-pix_fmt" || strings.HasPrefix(option, "-pix_fmt:"):
+			if !caps.HasPixFmt(value) {
+				errs = append(errs, fmt.Errorf("pixel format %q is not supported", value))
+			}
+		}
+	}
+
+	return errs
+}
+
+// Validate checks if all the filters, encoders, muxers and pixel formats used by the ffmpeg command and its pre-commands are supported.
+// It reports all problems at once by errors.Join().
+// caps: capabilities of the ffmpeg build to run the command. Use DiscoverCapabilities() to get it.
+func (ff *FFmpeg) Validate(caps *Capabilities) error {
+	var errs []error
+
+	for _, cmd := range ff.preCmds {
+		if pre, ok := cmd.(*FFmpeg); ok {
+			if err := pre.Validate(caps); err != nil {
+				errs = append(errs, fmt.Errorf("pre-command: %w", err))
+			}
+		}
+	}
+
+	checked := make(map[string]struct{})
+	for _, fc := range ff.fg {
+		for _, filter := range fc.filters {
+			name := filterName(filter)
+			if _, ok := checked[name]; ok {
+				continue
+			}
+			checked[name] = struct{}{}
+
+			if !caps.HasFilter(name) {
+				errs = append(errs, fmt.Errorf("filter %q is not supported", name))
+			}
+		}
+	}
+
+	// Codecs of input options are decoders.
+	for _, options := range ff.inputOptions {
+		errs = append(errs, caps.checkOptions(options, true)...)
+	}
+
+	errs = append(errs, caps.checkOptions(ff.outputOptions, false)...)
+
+	return errors.Join(errs...)
+}
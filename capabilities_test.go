@@ -0,0 +1,129 @@
+package ffcmd_test
+
+import (
+	"fmt"
+
+	"github.com/northbright/ffcmd"
+)
+
+func ExampleParseCapabilities() {
+	// Outputs of "ffmpeg -hide_banner -version / -filters / -encoders / -decoders / -muxers / -pix_fmts"(truncated).
+	// Use ffcmd.DiscoverCapabilities() to run ffmpeg and parse the outputs.
+	version := `ffmpeg version 6.1.1 Copyright (c) 2000-2023 the FFmpeg developers
+built with gcc 13 (Ubuntu 13.2.0-23ubuntu3)
+configuration: --prefix=/usr --enable-gpl --enable-libx264 --enable-libwebp
+libavutil      58. 29.100 / 58. 29.100`
+
+	filters := `Filters:
+  T.. = Timeline support
+  .S. = Slice threading
+  ..C = Command support
+  A = Audio input/output
+  V = Video input/output
+  N = Dynamic number and/or type of input/output
+  | = Source or sink filter
+ ... concat            N->N       Concatenate audio and video streams.
+ TSC overlay           VV->V      Overlay a video source on top of the input.
+ ..C scale             V->V       Scale the input video size and/or convert the image format.
+ T.C volume            A->A       Change input volume.`
+
+	encoders := `Encoders:
+ V..... = Video
+ A..... = Audio
+ S..... = Subtitle
+ .F.... = Frame-level multithreading
+ ..S... = Slice-level multithreading
+ ...X.. = Codec is experimental
+ ....B. = Supports draw_horiz_band
+ .....D = Supports direct rendering method 1
+ ------
+ V....D libx264              libx264 H.264 / AVC / MPEG-4 AVC / MPEG-4 part 10 (codec h264)
+ V....D libwebp              libwebp WebP image (codec webp)
+ A....D aac                  AAC (Advanced Audio Coding)`
+
+	decoders := `Decoders:
+ V..... = Video
+ A..... = Audio
+ S..... = Subtitle
+ .F.... = Frame-level multithreading
+ ..S... = Slice-level multithreading
+ ...X.. = Codec is experimental
+ ....B. = Supports draw_horiz_band
+ .....D = Supports direct rendering method 1
+ ------
+ VFS..D h264                 H.264 / AVC / MPEG-4 AVC / MPEG-4 part 10
+ A....D aac                  AAC (Advanced Audio Coding)`
+
+	muxers := `File formats:
+ D. = Demuxing supported
+ .E = Muxing supported
+ --
+  E mp4             MP4 (MPEG-4 Part 14)
+  E null            raw null video
+  E webm            WebM`
+
+	pixFmts := `Pixel formats:
+I.... = Supported Input  format for conversion
+.O... = Supported Output format for conversion
+..H.. = Hardware accelerated format
+...P. = Paletted format
+....B = Bitstream format
+FLAGS NAME            NB_COMPONENTS BITS_PER_PIXEL BIT_DEPTHS
+-----
+IO... yuv420p                3             12      8-8-8
+IO... yuv420p10le            3             15      10-10-10`
+
+	caps, err := ffcmd.ParseCapabilities(version, filters, encoders, decoders, muxers, pixFmts)
+	if err != nil {
+		fmt.Printf("ffcmd.ParseCapabilities() error: %v", err)
+		return
+	}
+
+	fmt.Printf("version: %s, configuration: %v\n", caps.Version, caps.Configuration)
+	fmt.Printf("filters: %d, encoders: %d, decoders: %d, muxers: %d, pixel formats: %d\n", len(caps.Filters), len(caps.Encoders), len(caps.Decoders), len(caps.Muxers), len(caps.PixFmts))
+	fmt.Printf("overlay: %+v\n", caps.Filters["overlay"])
+	fmt.Printf("libx264: %+v\n", caps.Encoders["libx264"])
+
+	// Validate the command before running.
+	ff := ffcmd.New("output.mp4", true)
+	ff.AddInput("input.mov")
+
+	v := ffcmd.NewFilterChain("[outv]")
+	v.AddInputByID(0, "v", 0)
+	v.Chain("scale=1280:720").Chain("subtitles=input.srt")
+	ff.Chain(v)
+
+	ff.AddOutputOptions("-c:v", "libx265", "-pix_fmt", "yuv420p")
+
+	if err := ff.Validate(caps); err != nil {
+		fmt.Printf("ff.Validate() error:\n%v\n", err)
+	}
+
+	// ffmpeg >= 6 has the third flag column of devices in the output of "-muxers".
+	muxers = `File formats:
+ D.. = Demuxing supported
+ .E. = Muxing supported
+ ..d = Is a device
+ ---
+  E  mp4             MP4 (MPEG-4 Part 14)
+ DE  matroska,webm   Matroska / WebM
+  Ed alsa            ALSA audio output`
+
+	caps, err = ffcmd.ParseCapabilities(version, filters, encoders, decoders, muxers, pixFmts)
+	if err != nil {
+		fmt.Printf("ffcmd.ParseCapabilities() error: %v", err)
+		return
+	}
+
+	fmt.Printf("muxers: %d, mp4: %v, webm: %v, alsa: %v\n", len(caps.Muxers), caps.HasMuxer("mp4"), caps.HasMuxer("webm"), caps.HasMuxer("alsa"))
+
+	// Output:
+	// version: 6.1.1, configuration: [--prefix=/usr --enable-gpl --enable-libx264 --enable-libwebp]
+	// filters: 4, encoders: 3, decoders: 2, muxers: 3, pixel formats: 2
+	// overlay: {Name:overlay Timeline:true SliceThreading:true Command:true Inputs:VV Outputs:V Description:Overlay a video source on top of the input.}
+	// libx264: {Name:libx264 Type:video Experimental:false Description:libx264 H.264 / AVC / MPEG-4 AVC / MPEG-4 part 10 (codec h264)}
+	// ff.Validate() error:
+	// filter "subtitles" is not supported
+	// encoder "libx265" is not supported
+	// muxers: 4, mp4: true, webm: true, alsa: true
+}
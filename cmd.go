@@ -1,6 +1,7 @@
 package ffcmd
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
@@ -15,6 +16,21 @@ type Cmd interface {
 	Run(dir string, fn ReadOutputFunc) error
 }
 
+// Resolver is implemented by the commands which need to resolve values(e.g. probe media files) when running.
+// The resolved values are used by the returned command string only, the command itself is not changed.
+// FFmpeg.Run() runs the resolved command strings of the pre-commands and post-commands.
+type Resolver interface {
+	ResolvedString(ctx context.Context, dir string) (string, error)
+}
+
+// resolvedString returns the resolved command string if the command implements Resolver, or the command string.
+func resolvedString(ctx context.Context, dir string, cmd Cmd) (string, error) {
+	if r, ok := cmd.(Resolver); ok {
+		return r.ResolvedString(ctx, dir)
+	}
+	return cmd.String()
+}
+
 func RunCmd(dir, cmdStr string, fn ReadOutputFunc) error {
 	cmd := exec.Command("bash", "-c", cmdStr)
 
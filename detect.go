@@ -0,0 +1,277 @@
+package ffcmd
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// edgeTolerance is the max distance in seconds from the start / end of the input
+// for an interval to be treated as leading / trailing.
+const edgeTolerance = 0.1
+
+var (
+	inputDurationRegexp = regexp.MustCompile(`Duration: (\d{2}):(\d{2}):(\d{2}(?:\.\d+)?)`)
+	silenceStartRegexp  = regexp.MustCompile(`silence_start: (\S+)`)
+	silenceEndRegexp    = regexp.MustCompile(`silence_end: (\S+)`)
+	blackRegexp         = regexp.MustCompile(`black_start:\s*(\S+)\s+black_end:\s*(\S+)`)
+)
+
+// newTimeRange returns the time range from start to end in seconds. Negative start(e.g. silence_start: -0.001) is treated as 0.
+func newTimeRange(start, end float64) (TimeRange, error) {
+	start = max(start, 0)
+
+	s, err := NewTimestampFromSecond(float32(start))
+	if err != nil {
+		return TimeRange{}, fmt.Errorf("NewTimestampFromSecond() error: %v", err)
+	}
+
+	e, err := NewTimestampFromSecond(float32(end))
+	if err != nil {
+		return TimeRange{}, fmt.Errorf("NewTimestampFromSecond() error: %v", err)
+	}
+
+	return TimeRange{Start: s, End: e}, nil
+}
+
+// parseSecond parses the seconds printed by filters.
+func parseSecond(name, str string) (float64, error) {
+	v, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %q", name, str)
+	}
+	return v, nil
+}
+
+// inputDuration returns the duration of input in seconds printed by ffmpeg(e.g. "Duration: 00:01:05.12").
+func inputDuration(output string) (float64, bool) {
+	m := inputDurationRegexp.FindStringSubmatch(output)
+	if m == nil {
+		return 0, false
+	}
+
+	hh, _ := strconv.Atoi(m[1])
+	mm, _ := strconv.Atoi(m[2])
+	ss, _ := strconv.ParseFloat(m[3], 64)
+
+	return float64(hh*3600+mm*60) + ss, true
+}
+
+// SilenceDetectFilter returns the silencedetect filter to print the silence intervals to stderr.
+// noise: noise tolerance in dB(e.g. -50).
+// duration: min duration of silence in seconds.
+func SilenceDetectFilter(noise, duration float32) (string, error) {
+	if noise >= 0 {
+		return "", fmt.Errorf("invalid noise: %g", noise)
+	}
+
+	if duration <= 0 {
+		return "", fmt.Errorf("invalid duration: %g", duration)
+	}
+
+	return fmt.Sprintf("silencedetect=noise=%gdB:d=%g", noise, duration), nil
+}
+
+// ParseSilenceDetectOutput parses the output(stderr) of ffmpeg with silencedetect filter and returns the silence intervals.
+// silencedetect does not print silence_end for the trailing silence in old versions of ffmpeg,
+// the duration of input printed by ffmpeg is used as the end.
+func ParseSilenceDetectOutput(output string) ([]TimeRange, error) {
+	var (
+		ranges []TimeRange
+		start  *float64
+	)
+
+	for _, line := range strings.Split(output, "\n") {
+		if m := silenceStartRegexp.FindStringSubmatch(line); m != nil {
+			v, err := parseSecond("silence_start", m[1])
+			if err != nil {
+				return nil, err
+			}
+			start = &v
+			continue
+		}
+
+		if m := silenceEndRegexp.FindStringSubmatch(line); m != nil {
+			if start == nil {
+				return nil, fmt.Errorf("silence_end without silence_start")
+			}
+
+			end, err := parseSecond("silence_end", m[1])
+			if err != nil {
+				return nil, err
+			}
+
+			r, err := newTimeRange(*start, end)
+			if err != nil {
+				return nil, err
+			}
+			ranges = append(ranges, r)
+			start = nil
+		}
+	}
+
+	if start != nil {
+		end, ok := inputDuration(output)
+		if !ok {
+			return nil, fmt.Errorf("no silence_end and duration of input for the trailing silence")
+		}
+
+		r, err := newTimeRange(*start, end)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+
+	return ranges, nil
+}
+
+// DetectSilence runs ffmpeg to detect the silence intervals of the audio stream of the input.
+// ctx: context to cancel ffmpeg.
+// dir: working dir to run ffmpeg.
+// input: input file.
+// streamID: 0-based audio stream ID.
+// noise, duration: see SilenceDetectFilter().
+func DetectSilence(ctx context.Context, dir, input string, streamID int, noise, duration float32) ([]TimeRange, error) {
+	filter, err := SilenceDetectFilter(noise, duration)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := runFFmpeg(ctx, dir, "-i", input, "-map", fmt.Sprintf("0:a:%d", streamID), "-af", filter, "-f", "null", "-")
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseSilenceDetectOutput(string(out))
+}
+
+// BlackDetectFilter returns the blackdetect filter to print the black intervals to stderr.
+// duration: min duration of black in seconds.
+// pictureThreshold: ratio of black pixels for a picture to be black(e.g. 0.98).
+// pixelThreshold: luminance threshold for a pixel to be black(e.g. 0.1).
+func BlackDetectFilter(duration, pictureThreshold, pixelThreshold float32) (string, error) {
+	if duration <= 0 {
+		return "", fmt.Errorf("invalid duration: %g", duration)
+	}
+
+	if pictureThreshold < 0 || pictureThreshold > 1 {
+		return "", fmt.Errorf("invalid picture threshold: %g", pictureThreshold)
+	}
+
+	if pixelThreshold < 0 || pixelThreshold > 1 {
+		return "", fmt.Errorf("invalid pixel threshold: %g", pixelThreshold)
+	}
+
+	return fmt.Sprintf("blackdetect=d=%g:pic_th=%g:pix_th=%g", duration, pictureThreshold, pixelThreshold), nil
+}
+
+// ParseBlackDetectOutput parses the output(stderr) of ffmpeg with blackdetect filter and returns the black intervals.
+func ParseBlackDetectOutput(output string) ([]TimeRange, error) {
+	var ranges []TimeRange
+
+	for _, line := range strings.Split(output, "\n") {
+		m := blackRegexp.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		start, err := parseSecond("black_start", m[1])
+		if err != nil {
+			return nil, err
+		}
+
+		end, err := parseSecond("black_end", m[2])
+		if err != nil {
+			return nil, err
+		}
+
+		r, err := newTimeRange(start, end)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+
+	return ranges, nil
+}
+
+// DetectBlack runs ffmpeg to detect the black intervals of the first video stream of the input.
+// ctx: context to cancel ffmpeg.
+// dir: working dir to run ffmpeg.
+// input: input file.
+// duration, pictureThreshold, pixelThreshold: see BlackDetectFilter().
+func DetectBlack(ctx context.Context, dir, input string, duration, pictureThreshold, pixelThreshold float32) ([]TimeRange, error) {
+	filter, err := BlackDetectFilter(duration, pictureThreshold, pixelThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := runFFmpeg(ctx, dir, "-i", input, "-map", "0:v:0", "-vf", filter, "-f", "null", "-")
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseBlackDetectOutput(string(out))
+}
+
+// Trim chains trim(or atrim for audio) filter to the filterchain to keep the range only and resets the timestamps.
+func (r *TimeRange) Trim(fc *FilterChain, audio bool) {
+	opts := fmt.Sprintf("start=%s:end=%s", r.Start.Second(), r.End.Second())
+
+	if audio {
+		fc.Chain("atrim=" + opts).Chain("asetpts=PTS-STARTPTS")
+	} else {
+		fc.Chain("trim=" + opts).Chain("setpts=PTS-STARTPTS")
+	}
+}
+
+// TrimEdges trims the leading and trailing intervals(e.g. silence, black) of the clip and returns the kept range.
+// Intervals in the middle are kept.
+// v, a: video / audio filterchains of the clip to chain trim / atrim filters. nil filterchains are skipped.
+// intervals: intervals returned by DetectSilence(), DetectBlack() or the parse functions.
+// duration: duration of the clip in seconds. Use Prober to get it.
+func TrimEdges(v, a *FilterChain, intervals []TimeRange, duration float32) (*TimeRange, error) {
+	start, end := 0.0, float64(duration)
+
+	// Sort intervals by start time to merge the adjacent leading / trailing intervals(e.g. black followed by silence).
+	sorted := slices.Clone(intervals)
+	slices.SortFunc(sorted, func(a, b TimeRange) int {
+		return cmp.Compare(a.Start.seconds(), b.Start.seconds())
+	})
+
+	for _, r := range sorted {
+		if s, e := r.Start.seconds(), r.End.seconds(); s <= start+edgeTolerance && e > start {
+			start = e
+		}
+	}
+
+	for _, r := range slices.Backward(sorted) {
+		if s, e := r.Start.seconds(), r.End.seconds(); e >= end-edgeTolerance && s < end {
+			end = s
+		}
+	}
+
+	if end <= start {
+		return nil, fmt.Errorf("nothing left after trimming")
+	}
+
+	r, err := newTimeRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	if v != nil {
+		r.Trim(v, false)
+	}
+
+	if a != nil {
+		r.Trim(a, true)
+	}
+
+	return &r, nil
+}
@@ -0,0 +1,91 @@
+package ffcmd_test
+
+import (
+	"fmt"
+
+	"github.com/northbright/ffcmd"
+)
+
+func ExampleTrimEdges() {
+	// Output(stderr) of:
+	// ffmpeg -i input.mp4 -map 0:a:0 -af silencedetect=noise=-50dB:d=0.5 -f null -
+	// Use ffcmd.DetectSilence() to run ffmpeg and parse the output.
+	// The trailing silence has no silence_end in old versions of ffmpeg and the duration of input is used.
+	silenceStderr := `Input #0, mov,mp4,m4a,3gp,3g2,mj2, from 'input.mp4':
+  Duration: 00:01:00.03, start: 0.000000, bitrate: 4512 kb/s
+[silencedetect @ 0x600001f58000] silence_start: -0.00133
+[silencedetect @ 0x600001f58000] silence_end: 1.52 | silence_duration: 1.52133
+[silencedetect @ 0x600001f58000] silence_start: 30.112
+[silencedetect @ 0x600001f58000] silence_end: 31.004 | silence_duration: 0.892
+[silencedetect @ 0x600001f58000] silence_start: 58.3`
+
+	silences, err := ffcmd.ParseSilenceDetectOutput(silenceStderr)
+	if err != nil {
+		fmt.Printf("ffcmd.ParseSilenceDetectOutput() error: %v", err)
+		return
+	}
+
+	for _, r := range silences {
+		fmt.Printf("silence: %s - %s\n", r.Start, r.End)
+	}
+
+	// Output(stderr) of:
+	// ffmpeg -i input.mp4 -map 0:v:0 -vf blackdetect=d=0.5:pic_th=0.98:pix_th=0.1 -f null -
+	// Use ffcmd.DetectBlack() to run ffmpeg and parse the output.
+	blackStderr := `[blackdetect @ 0x600003c5c000] black_start:0 black_end:2.002 black_duration:2.002
+[blackdetect @ 0x600003c5c000] black_start:59.026 black_end:60.027 black_duration:1.001`
+
+	blacks, err := ffcmd.ParseBlackDetectOutput(blackStderr)
+	if err != nil {
+		fmt.Printf("ffcmd.ParseBlackDetectOutput() error: %v", err)
+		return
+	}
+
+	for _, r := range blacks {
+		fmt.Printf("black: %s - %s\n", r.Start, r.End)
+	}
+
+	// Trim the leading / trailing black and silence of the clip.
+	ff := ffcmd.New("output.mp4", true)
+	ff.AddInput("input.mp4")
+
+	v := ffcmd.NewFilterChain("[outv]")
+	v.AddInputByID(0, "v", 0)
+
+	a := ffcmd.NewFilterChain("[outa]")
+	a.AddInputByID(0, "a", 0)
+
+	r, err := ffcmd.TrimEdges(v, a, append(blacks, silences...), 60.027)
+	if err != nil {
+		fmt.Printf("ffcmd.TrimEdges() error: %v", err)
+		return
+	}
+	fmt.Printf("kept: %s - %s, duration: %.3f\n", r.Start, r.End, r.Duration())
+
+	ff.Chain(v)
+	ff.Chain(a)
+	ff.MapByOutputs(v)
+
+	str, err := ff.String()
+	if err != nil {
+		fmt.Printf("ff.String() error: %v", err)
+		return
+	}
+	fmt.Printf("%s\n", str)
+
+	// Output:
+	// silence: 00:00:00.000 - 00:00:01.520
+	// silence: 00:00:30.112 - 00:00:31.004
+	// silence: 00:00:58.300 - 00:01:00.030
+	// black: 00:00:00.000 - 00:00:02.002
+	// black: 00:00:59.026 - 00:01:00.027
+	// kept: 00:00:02.002 - 00:00:58.300, duration: 56.298
+	// echo "y" | ffmpeg \
+	// -i "input.mp4" \
+	// -filter_complex " \
+	// [0:v:0]trim=start=2.002:end=58.300,setpts=PTS-STARTPTS[outv];
+	// [0:a:0]atrim=start=2.002:end=58.300,asetpts=PTS-STARTPTS[outa]" \
+	// -map "[outa]" \
+	// -map "[outv]" \
+	// output.mp4
+}
@@ -0,0 +1,121 @@
+package ffcmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DrawText represents the options of drawtext filter to burn text(e.g. captions, titles) into video.
+type DrawText struct {
+	// Text is the text to draw. It's escaped automatically.
+	Text string
+	// TextFile is the file which contains the text to draw. It overrides Text.
+	// Use it with NewCreateFileCmd() / NewRemoveFileCmd() as ffmpeg's pre / post commands for long or complex text.
+	TextFile string
+	// Expand enables text expansion(e.g. "%{pts:hms}").
+	// Otherwise, the text is drawn literally.
+	Expand bool
+	// FontFile is the font file to draw text.
+	FontFile string
+	// Font is the font family to draw text(e.g. "Sans"). It requires fontconfig.
+	Font string
+	// FontSize is the font size. 0 means default(16).
+	FontSize int
+	// FontColor is the color of text(e.g. "white", "#FFFFFF", "white@0.8"). Empty means default(black).
+	FontColor string
+	// Box draws a box with BoxColor around the text.
+	Box bool
+	// BoxColor is the color of box(e.g. "black@0.5"). Empty means default(white).
+	BoxColor string
+	// BoxBorderW is the width of the border around the text.
+	BoxBorderW int
+	// ShadowColor is the color of shadow. Empty means no shadow.
+	ShadowColor string
+	// ShadowX, ShadowY are the x / y offsets of shadow.
+	ShadowX int
+	ShadowY int
+	// Anchor is the position to place the text.
+	Anchor Anchor
+	// X, Y are the x / y expressions of drawtext filter used for AnchorCustom(e.g. Mul(VarW, Const(0.1))).
+	X Expr
+	Y Expr
+	// Margin is the margin in pixels to the edges of video.
+	Margin int
+	// Start, End are the timestamps in the "HH:MM:SS(.mmm)" format to show the text.
+	// The text is shown all the time if both are empty.
+	Start string
+	End   string
+	// Enable is the custom expression to show the text. It overrides Start and End.
+	Enable Expr
+}
+
+// Filter returns the drawtext filter string to chain.
+func (dt *DrawText) Filter() (string, error) {
+	if dt.Text == "" && dt.TextFile == "" {
+		return "", fmt.Errorf("both text and text file are empty")
+	}
+
+	if dt.FontSize < 0 {
+		return "", fmt.Errorf("invalid font size")
+	}
+
+	enable := dt.Enable
+	if enable == nil {
+		var err error
+		if enable, err = enableExpr(dt.Start, dt.End); err != nil {
+			return "", fmt.Errorf("enableExpr() error: %v", err)
+		}
+	}
+
+	var opts []string
+
+	if dt.FontFile != "" {
+		opts = append(opts, "fontfile="+EscapeFilterOptionValue(dt.FontFile))
+	}
+
+	if dt.Font != "" {
+		opts = append(opts, "font="+EscapeFilterOptionValue(dt.Font))
+	}
+
+	if dt.TextFile != "" {
+		opts = append(opts, "textfile="+EscapeFilterOptionValue(dt.TextFile))
+	} else {
+		opts = append(opts, "text="+EscapeFilterOptionValue(dt.Text))
+	}
+
+	if !dt.Expand {
+		opts = append(opts, "expansion=none")
+	}
+
+	if dt.FontSize > 0 {
+		opts = append(opts, fmt.Sprintf("fontsize=%d", dt.FontSize))
+	}
+
+	if dt.FontColor != "" {
+		opts = append(opts, "fontcolor="+dt.FontColor)
+	}
+
+	if dt.Box {
+		opts = append(opts, "box=1")
+		if dt.BoxColor != "" {
+			opts = append(opts, "boxcolor="+dt.BoxColor)
+		}
+		if dt.BoxBorderW > 0 {
+			opts = append(opts, fmt.Sprintf("boxborderw=%d", dt.BoxBorderW))
+		}
+	}
+
+	if dt.ShadowColor != "" {
+		opts = append(opts, "shadowcolor="+dt.ShadowColor)
+		opts = append(opts, fmt.Sprintf("shadowx=%d:shadowy=%d", dt.ShadowX, dt.ShadowY))
+	}
+
+	x, y := anchorXY(dt.Anchor, dt.Margin, Var("w"), Var("h"), Var("text_w"), Var("text_h"), dt.X, dt.Y)
+	opts = append(opts, "x="+FormatExpr(x), "y="+FormatExpr(y))
+
+	if enable != nil {
+		opts = append(opts, "enable="+FormatExpr(enable))
+	}
+
+	return "drawtext=" + strings.Join(opts, ":"), nil
+}
@@ -0,0 +1,58 @@
+package ffcmd_test
+
+import (
+	"fmt"
+
+	"github.com/northbright/ffcmd"
+)
+
+func ExampleDrawText() {
+	ffmpeg := ffcmd.New("output.mp4", true)
+
+	v := ffcmd.NewFilterChain("[outv]")
+	v.AddInputByID(ffmpeg.AddInput("input.mp4"), "v", 0)
+
+	// Draw a title at the bottom center from 0s to 3s.
+	// Special characters(e.g. ', :, %, \) in text are escaped automatically.
+	dt := &ffcmd.DrawText{
+		Text:        `It's 10:30, 100% \o/`,
+		FontFile:    "fonts/NotoSans-Regular.ttf",
+		FontSize:    36,
+		FontColor:   "white",
+		Box:         true,
+		BoxColor:    "black@0.5",
+		BoxBorderW:  10,
+		ShadowColor: "black",
+		ShadowX:     2,
+		ShadowY:     2,
+		Anchor:      ffcmd.AnchorBottom,
+		Margin:      40,
+		Start:       "00:00:00",
+		End:         "00:00:03",
+	}
+
+	drawtext, err := dt.Filter()
+	if err != nil {
+		fmt.Printf("dt.Filter() error: %v", err)
+		return
+	}
+
+	v.Chain(drawtext)
+	ffmpeg.Chain(v)
+
+	str, err := ffmpeg.String()
+	if err != nil {
+		fmt.Printf("ffmpeg.String() error: %v", err)
+		return
+	}
+
+	fmt.Println(str)
+
+	// Output:
+	// echo "y" | ffmpeg \
+	// -i "input.mp4" \
+	// -filter_complex " \
+	// [0:v:0]drawtext=fontfile=fonts/NotoSans-Regular.ttf:text=It\\\\\\'s 10\\\\:30\\, 100% \\\\\\\\o/:expansion=none:fontsize=36:fontcolor=white:box=1:boxcolor=black@0.5:boxborderw=10:shadowcolor=black:shadowx=2:shadowy=2:x=(w-text_w)/2:y=h-text_h-40:enable='between(t,0,3)'[outv]" \
+	// -map "[outv]" \
+	// output.mp4
+}
@@ -0,0 +1,246 @@
+package ffcmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Expr represents an expression used by filter options(e.g. "enable", "x" / "y" of overlay, "setpts", "select", "volume").
+// See https://ffmpeg.org/ffmpeg-utils.html#Expression-Evaluation for more.
+type Expr interface {
+	// String returns the expression string.
+	String() string
+	// precedence returns the precedence of the expression to determine if parentheses are needed.
+	precedence() int
+}
+
+// Precedences of expressions.
+const (
+	precRaw = iota
+	precAdd
+	precMul
+	precPow
+	precAtom
+)
+
+// constExpr represents a numeric constant.
+type constExpr struct {
+	v float64
+}
+
+func (e constExpr) String() string {
+	return strconv.FormatFloat(e.v, 'f', -1, 64)
+}
+
+func (e constExpr) precedence() int {
+	// Negative constant needs parentheses as an operand.
+	if e.v < 0 {
+		return precRaw
+	}
+	return precAtom
+}
+
+// varExpr represents a variable(e.g. "t", "n", "w", "h").
+type varExpr struct {
+	name string
+}
+
+func (e varExpr) String() string {
+	return e.name
+}
+
+func (e varExpr) precedence() int {
+	return precAtom
+}
+
+// rawExpr represents a raw expression string.
+type rawExpr struct {
+	s string
+}
+
+func (e rawExpr) String() string {
+	return e.s
+}
+
+func (e rawExpr) precedence() int {
+	// Raw expression is always enclosed in parentheses as an operand.
+	return precRaw
+}
+
+// binaryExpr represents an arithmetic expression with 2 operands.
+type binaryExpr struct {
+	op   string
+	prec int
+	l    Expr
+	r    Expr
+}
+
+func (e binaryExpr) String() string {
+	l := e.l.String()
+	if e.l.precedence() < e.prec {
+		l = "(" + l + ")"
+	}
+
+	r := e.r.String()
+	// Right operand with the same precedence needs parentheses for "-" and "/"(e.g. "a-(b-c)").
+	if e.r.precedence() < e.prec || (e.r.precedence() == e.prec && (e.op == "-" || e.op == "/" || e.op == "^")) {
+		r = "(" + r + ")"
+	}
+
+	return l + e.op + r
+}
+
+func (e binaryExpr) precedence() int {
+	return e.prec
+}
+
+// callExpr represents a function call(e.g. "between(t,1,2)").
+type callExpr struct {
+	name string
+	args []Expr
+}
+
+func (e callExpr) String() string {
+	var args []string
+	for _, arg := range e.args {
+		args = append(args, arg.String())
+	}
+	return fmt.Sprintf("%s(%s)", e.name, strings.Join(args, ","))
+}
+
+func (e callExpr) precedence() int {
+	return precAtom
+}
+
+// Common variables of expressions.
+var (
+	// VarT is the timestamp in seconds.
+	VarT = Var("t")
+	// VarN is the number of the input frame, starting from 0.
+	VarN = Var("n")
+	// VarW, VarH are the width / height of the video(e.g. drawtext) or the main video(e.g. overlay).
+	VarW = Var("W")
+	VarH = Var("H")
+	// VarPTS, VarStartPTS are the presentation timestamp of the frame and the first frame.
+	VarPTS      = Var("PTS")
+	VarStartPTS = Var("STARTPTS")
+	// VarTB is the timebase of the input timestamps.
+	VarTB = Var("TB")
+)
+
+// Const returns a numeric constant expression.
+func Const(v float64) Expr {
+	return constExpr{v}
+}
+
+// Var returns a variable expression(e.g. "t", "n", "main_w", "overlay_w", "text_w").
+func Var(name string) Expr {
+	return varExpr{name}
+}
+
+// Raw returns an expression by raw string.
+// It's enclosed in parentheses when used as an operand.
+func Raw(s string) Expr {
+	return rawExpr{s}
+}
+
+// Add returns the expression of "a+b".
+func Add(a, b Expr) Expr {
+	return binaryExpr{"+", precAdd, a, b}
+}
+
+// Sub returns the expression of "a-b".
+func Sub(a, b Expr) Expr {
+	return binaryExpr{"-", precAdd, a, b}
+}
+
+// Mul returns the expression of "a*b".
+func Mul(a, b Expr) Expr {
+	return binaryExpr{"*", precMul, a, b}
+}
+
+// Div returns the expression of "a/b".
+func Div(a, b Expr) Expr {
+	return binaryExpr{"/", precMul, a, b}
+}
+
+// Pow returns the expression of "a^b".
+func Pow(a, b Expr) Expr {
+	return binaryExpr{"^", precPow, a, b}
+}
+
+// Call returns the expression of function call(e.g. "sin(t)", "clip(x,0,1)").
+func Call(name string, args ...Expr) Expr {
+	return callExpr{name, args}
+}
+
+// Between returns the expression of "between(x,min,max)": 1 if x is in [min, max], 0 otherwise.
+func Between(x, min, max Expr) Expr {
+	return Call("between", x, min, max)
+}
+
+// If returns the expression of "if(cond,then)": then if cond is not 0, 0 otherwise.
+func If(cond, then Expr) Expr {
+	return Call("if", cond, then)
+}
+
+// IfElse returns the expression of "if(cond,then,els)": then if cond is not 0, els otherwise.
+func IfElse(cond, then, els Expr) Expr {
+	return Call("if", cond, then, els)
+}
+
+// Not returns the expression of "not(x)": 1 if x is 0, 0 otherwise.
+func Not(x Expr) Expr {
+	return Call("not", x)
+}
+
+// Eq returns the expression of "eq(a,b)": 1 if a == b, 0 otherwise.
+func Eq(a, b Expr) Expr {
+	return Call("eq", a, b)
+}
+
+// Lt returns the expression of "lt(a,b)": 1 if a < b, 0 otherwise.
+func Lt(a, b Expr) Expr {
+	return Call("lt", a, b)
+}
+
+// Lte returns the expression of "lte(a,b)": 1 if a <= b, 0 otherwise.
+func Lte(a, b Expr) Expr {
+	return Call("lte", a, b)
+}
+
+// Gt returns the expression of "gt(a,b)": 1 if a > b, 0 otherwise.
+func Gt(a, b Expr) Expr {
+	return Call("gt", a, b)
+}
+
+// Gte returns the expression of "gte(a,b)": 1 if a >= b, 0 otherwise.
+func Gte(a, b Expr) Expr {
+	return Call("gte", a, b)
+}
+
+// Min returns the expression of "min(a,b)".
+func Min(a, b Expr) Expr {
+	return Call("min", a, b)
+}
+
+// Max returns the expression of "max(a,b)".
+func Max(a, b Expr) Expr {
+	return Call("max", a, b)
+}
+
+// Clip returns the expression of "clip(x,min,max)".
+func Clip(x, min, max Expr) Expr {
+	return Call("clip", x, min, max)
+}
+
+// FormatExpr returns the expression string to use as a filter option value in filtergraph.
+// It's quoted with single quotes if it contains special characters of filtergraph(e.g. "," in function calls).
+func FormatExpr(e Expr) string {
+	s := e.String()
+	if strings.ContainsAny(s, `,;[]`) {
+		return fmt.Sprintf("'%s'", s)
+	}
+	return s
+}
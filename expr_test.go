@@ -0,0 +1,38 @@
+package ffcmd_test
+
+import (
+	"fmt"
+
+	"github.com/northbright/ffcmd"
+)
+
+func ExampleExpr() {
+	exprs := []ffcmd.Expr{
+		// Show between 1.5s and 5s.
+		ffcmd.Between(ffcmd.VarT, ffcmd.Const(1.5), ffcmd.Const(5)),
+		// Center horizontally.
+		ffcmd.Div(ffcmd.Sub(ffcmd.VarW, ffcmd.Var("text_w")), ffcmd.Const(2)),
+		// Slow down to half speed.
+		ffcmd.Mul(ffcmd.Const(2), ffcmd.Sub(ffcmd.VarPTS, ffcmd.VarStartPTS)),
+		// Parentheses are only added when needed.
+		ffcmd.Sub(ffcmd.Var("a"), ffcmd.Sub(ffcmd.Var("b"), ffcmd.Var("c"))),
+		ffcmd.Add(ffcmd.Mul(ffcmd.Var("a"), ffcmd.Var("b")), ffcmd.Const(-1)),
+		// Fade in volume in the first 3 seconds.
+		ffcmd.IfElse(ffcmd.Lt(ffcmd.VarT, ffcmd.Const(3)), ffcmd.Div(ffcmd.VarT, ffcmd.Const(3)), ffcmd.Const(1)),
+		// Select a frame every 10 seconds.
+		ffcmd.Gte(ffcmd.Sub(ffcmd.VarT, ffcmd.Var("prev_selected_t")), ffcmd.Const(10)),
+	}
+
+	for _, e := range exprs {
+		fmt.Printf("%s -> %s\n", e.String(), ffcmd.FormatExpr(e))
+	}
+
+	// Output:
+	// between(t,1.5,5) -> 'between(t,1.5,5)'
+	// (W-text_w)/2 -> (W-text_w)/2
+	// 2*(PTS-STARTPTS) -> 2*(PTS-STARTPTS)
+	// a-(b-c) -> a-(b-c)
+	// a*b+(-1) -> a*b+(-1)
+	// if(lt(t,3),t/3,1) -> 'if(lt(t,3),t/3,1)'
+	// gte(t-prev_selected_t,10) -> 'gte(t-prev_selected_t,10)'
+}
@@ -1,8 +1,10 @@
 package ffcmd
 
 import (
+	"context"
 	"fmt"
 	"sort"
+	"strings"
 )
 
 // FilterChain represents the filterchain of ffmpeg.
@@ -42,6 +44,45 @@ func (fc *FilterChain) AddInputByOutput(fcOut *FilterChain, outputID int) {
 	fc.inputs = append(fc.inputs, &filterChainOutputData{fcOut, outputID})
 }
 
+// NewSplitFilterChain returns a filterchain to split the output of another filterchain into n outputs(fan-out).
+// A labeled output of filterchain can only be consumed once.
+// Use the split filterchain's outputs instead when the output need to be consumed more than once.
+// fcOut, outputID: filterchain and 0-based index of its output to split.
+// streamType: "v" for video(split filter), "a" for audio(asplit filter).
+// n: number of outputs. Output labels are generated by the label to split(e.g. "[outv_0]", "[outv_1]" for "[outv]").
+func NewSplitFilterChain(fcOut *FilterChain, outputID int, streamType string, n int) (*FilterChain, error) {
+	if n < 2 {
+		return nil, fmt.Errorf("number of outputs should be at least 2")
+	}
+
+	var filter string
+	switch streamType {
+	case "v":
+		filter = "split"
+	case "a":
+		filter = "asplit"
+	default:
+		return nil, fmt.Errorf("unsupported stream type: %s", streamType)
+	}
+
+	label := fcOut.Output(outputID)
+	if label == "" {
+		return nil, fmt.Errorf("no output of filterchain for index: %d", outputID)
+	}
+	name := strings.ReplaceAll(labelName(label), ":", "_")
+
+	var outputs []string
+	for i := 0; i < n; i++ {
+		outputs = append(outputs, fmt.Sprintf("[%s_%d]", name, i))
+	}
+
+	fc := NewFilterChain(outputs...)
+	fc.AddInputByOutput(fcOut, outputID)
+	fc.Chain(fmt.Sprintf("%s=%d", filter, n))
+
+	return fc, nil
+}
+
 // Input returns the input string by 0-based index.
 func (fc *FilterChain) Input(id int) string {
 	if id < 0 || id >= len(fc.inputs) {
@@ -101,6 +142,7 @@ func (fc *FilterChain) Outputs() []string {
 }
 
 // Chain chains filter and returns a filterchain to chain next filter(e.g. fc.Chain("fps=30").Chain("scale=1280:720"))
+// It does not validate the filter. Call Validate() with the schemas of filters to check the options and pads.
 func (fc *FilterChain) Chain(filter string) *FilterChain {
 	if filter != "" {
 		fc.filters = append(fc.filters, filter)
@@ -135,15 +177,69 @@ func (fc *FilterChain) String() string {
 	return str
 }
 
+// labelName returns the name of the label in the "[OUTPUT_LABEL]" format(e.g. "outv" for "[outv]").
+func labelName(label string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(label, "["), "]")
+}
+
+// formatSecond returns the second string in the "s.mmm" format used by filter options.
+func formatSecond(second float32) string {
+	return fmt.Sprintf("%.3f", second)
+}
+
+// escapeChars escapes each character in chars with a backslash.
+func escapeChars(str, chars string) string {
+	var b strings.Builder
+
+	for _, r := range str {
+		if strings.ContainsRune(chars, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// EscapeFilterOptionValue escapes the value of filter option(e.g. text of drawtext filter, filename of subtitles filter) for filtergraph.
+// It escapes the special characters of filter options(\ ' :) and then the special characters of filtergraph(\ ' [ ] , ;).
+// See https://ffmpeg.org/ffmpeg-filters.html#Notes-on-filtergraph-escaping for more.
+func EscapeFilterOptionValue(value string) string {
+	value = escapeChars(value, `\':`)
+	return escapeChars(value, `\'[],;`)
+}
+
+// escapeDoubleQuoted escapes the special characters of string in double quotes for bash.
+func escapeDoubleQuoted(str string) string {
+	return escapeChars(str, "\\\"$`")
+}
+
+// joinOptions joins the command-line options with spaces and quotes the ones containing special characters for bash.
+func joinOptions(options []string) string {
+	var arr []string
+
+	for _, option := range options {
+		if option == "" || strings.ContainsAny(option, " \t\n\\\"'$`*?[]{}()<>|&;#~!") {
+			option = fmt.Sprintf("\"%s\"", escapeDoubleQuoted(option))
+		}
+		arr = append(arr, option)
+	}
+
+	return strings.Join(arr, " ")
+}
+
 // FFmpeg represents the ffmpeg command.
 type FFmpeg struct {
 	inputs          []string
+	inputOptions    [][]string
 	output          string
+	outputOptions   []string
 	fg              []*FilterChain
 	selectedStreams map[string]struct{}
 	preCmds         []Cmd
 	postCmds        []Cmd
 	overwrite       bool
+	mapMetadata     int
+	mapChapters     int
 }
 
 // New returns a new ffmpeg command.
@@ -151,16 +247,28 @@ type FFmpeg struct {
 // overwrite: if overwrite output when run ffmpeg command.
 // It'll failed to generate output if output exists and overwrite is set to false.
 func New(output string, overwrite bool) *FFmpeg {
-	return &FFmpeg{inputs: []string{}, output: output, fg: []*FilterChain{}, selectedStreams: make(map[string]struct{}), overwrite: overwrite}
+	return &FFmpeg{inputs: []string{}, output: output, fg: []*FilterChain{}, selectedStreams: make(map[string]struct{}), overwrite: overwrite, mapMetadata: -1, mapChapters: -1}
 }
 
 // AddInput adds input and returns index of the input.
 func (ff *FFmpeg) AddInput(in string) int {
+	return ff.AddInputWithOptions(in)
+}
+
+// AddInputWithOptions adds input with the input options and returns index of the input.
+// options: input options placed before "-i"(e.g. "-ss", "00:01:00.000", "-t", "10").
+func (ff *FFmpeg) AddInputWithOptions(in string, options ...string) int {
 	id := len(ff.inputs)
 	ff.inputs = append(ff.inputs, in)
+	ff.inputOptions = append(ff.inputOptions, options)
 	return id
 }
 
+// AddOutputOptions adds the output options placed before output(e.g. "-frames:v", "1").
+func (ff *FFmpeg) AddOutputOptions(options ...string) {
+	ff.outputOptions = append(ff.outputOptions, options...)
+}
+
 // AddPreCmd adds the command(set-up) to run before ffmpeg.
 func (ff *FFmpeg) AddPreCmd(cmd Cmd) {
 	ff.preCmds = append(ff.preCmds, cmd)
@@ -187,7 +295,8 @@ func (ff *FFmpeg) Map(stream string) {
 
 // MapByID selects stream by input index, stream type and index of stream as ffmpeg output.
 func (ff *FFmpeg) MapByID(inputID int, streamType string, streamID int) {
-	stream := fmt.Sprintf("[%d:%s:%d]", inputID, streamType, streamID)
+	// Input streams are mapped by stream specifiers without brackets(e.g. "0:a:0"). Brackets are for labels of filtergraph.
+	stream := fmt.Sprintf("%d:%s:%d", inputID, streamType, streamID)
 	if _, ok := ff.selectedStreams[stream]; !ok {
 		ff.selectedStreams[stream] = struct{}{}
 	}
@@ -206,11 +315,79 @@ func (ff *FFmpeg) MapByOutputs(fc *FilterChain) {
 	}
 }
 
+// MapMetadata copies the global metadata(tags) of the input to the output.
+// inputID: 0-based input ID. -1 means default(ffmpeg copies metadata from the first input).
+func (ff *FFmpeg) MapMetadata(inputID int) {
+	ff.mapMetadata = inputID
+}
+
+// MapChapters copies the chapters of the input to the output.
+// inputID: 0-based input ID. -1 means default(ffmpeg copies chapters from the first input with chapters).
+func (ff *FFmpeg) MapChapters(inputID int) {
+	ff.mapChapters = inputID
+}
+
+// checkFanOut checks if any labeled output of the filterchains is consumed more than once.
+func (ff *FFmpeg) checkFanOut() error {
+	produced := make(map[string]struct{})
+	consumed := make(map[string]int)
+
+	for _, fc := range ff.fg {
+		if len(fc.filters) == 0 {
+			continue
+		}
+
+		for _, output := range fc.outputs {
+			produced[output] = struct{}{}
+		}
+
+		for _, input := range fc.Inputs() {
+			consumed[input]++
+		}
+	}
+
+	for stream := range ff.selectedStreams {
+		consumed[stream]++
+	}
+
+	var labels []string
+	for label := range produced {
+		if consumed[label] > 1 {
+			labels = append(labels, label)
+		}
+	}
+
+	if len(labels) > 0 {
+		sort.Strings(labels)
+		return fmt.Errorf("outputs consumed more than once, use NewSplitFilterChain() to fan out: %s", strings.Join(labels, ", "))
+	}
+
+	return nil
+}
+
 // String returns the ffmpeg command string to run.
+// Use ResolvedString() to resolve the pre-commands and post-commands(e.g. end time of SRT file) by probing in Go.
 func (ff *FFmpeg) String() (string, error) {
+	return ff.string(func(cmd Cmd) (string, error) {
+		return cmd.String()
+	})
+}
+
+// ResolvedString returns the ffmpeg command string with the pre-commands and post-commands resolved(e.g. probe the duration of video for the end time of SRT file).
+// The commands are not changed, the values are resolved every time it's called.
+// ctx: context to cancel the resolving.
+// dir: working dir to find the files. It should be the same dir passed to Run().
+func (ff *FFmpeg) ResolvedString(ctx context.Context, dir string) (string, error) {
+	return ff.string(func(cmd Cmd) (string, error) {
+		return resolvedString(ctx, dir, cmd)
+	})
+}
+
+// string returns the ffmpeg command string with the strings of pre-commands and post-commands returned by cmdString.
+func (ff *FFmpeg) string(cmdString func(Cmd) (string, error)) (string, error) {
 	str := ""
 	for _, cmd := range ff.preCmds {
-		s, err := cmd.String()
+		s, err := cmdString(cmd)
 		if err != nil {
 			return "", fmt.Errorf("add pre-cmd error: %v", err)
 		}
@@ -224,11 +401,16 @@ func (ff *FFmpeg) String() (string, error) {
 
 	str += "ffmpeg \\\n"
 
-	for _, in := range ff.inputs {
-		str += fmt.Sprintf("-i \"%s\" \\\n", in)
+	for i, in := range ff.inputs {
+		if options := ff.inputOptions[i]; len(options) > 0 {
+			str += fmt.Sprintf("%s ", joinOptions(options))
+		}
+		str += fmt.Sprintf("-i \"%s\" \\\n", escapeDoubleQuoted(in))
 	}
 
-	str += "-filter_complex \" \\\n"
+	if len(ff.fg) > 0 {
+		str += "-filter_complex \" \\\n"
+	}
 
 	l := len(ff.fg)
 	for i, fc := range ff.fg {
@@ -237,7 +419,8 @@ func (ff *FFmpeg) String() (string, error) {
 			continue
 		}
 
-		str += s
+		// The filtergraph is in double quotes.
+		str += escapeDoubleQuoted(s)
 		if i < l-1 {
 			str += ";\n"
 		} else {
@@ -246,7 +429,13 @@ func (ff *FFmpeg) String() (string, error) {
 		}
 	}
 
-	str += "\" \\\n"
+	if len(ff.fg) > 0 {
+		str += "\" \\\n"
+	}
+
+	if err := ff.checkFanOut(); err != nil {
+		return "", fmt.Errorf("checkFanOut() error: %v", err)
+	}
 
 	// sort streams by names.
 	var selectedStreams []string
@@ -259,10 +448,22 @@ func (ff *FFmpeg) String() (string, error) {
 		str += fmt.Sprintf("-map \"%s\" \\\n", stream)
 	}
 
+	if ff.mapMetadata >= 0 {
+		str += fmt.Sprintf("-map_metadata %d \\\n", ff.mapMetadata)
+	}
+
+	if ff.mapChapters >= 0 {
+		str += fmt.Sprintf("-map_chapters %d \\\n", ff.mapChapters)
+	}
+
+	if len(ff.outputOptions) > 0 {
+		str += fmt.Sprintf("%s \\\n", joinOptions(ff.outputOptions))
+	}
+
 	str += ff.output
 
 	for _, cmd := range ff.postCmds {
-		s, err := cmd.String()
+		s, err := cmdString(cmd)
 		if err != nil {
 			return "", fmt.Errorf("add post-cmd error: %v", err)
 		}
@@ -273,9 +474,9 @@ func (ff *FFmpeg) String() (string, error) {
 }
 
 func (ff *FFmpeg) Run(dir string, fn ReadOutputFunc) error {
-	str, err := ff.String()
+	str, err := ff.ResolvedString(context.Background(), dir)
 	if err != nil {
-		return fmt.Errorf("ff.String() error: %v", err)
+		return fmt.Errorf("ff.ResolvedString() error: %v", err)
 	}
 
 	return RunCmd(dir, str, fn)
@@ -262,15 +262,13 @@ func Example() {
 			// Add command to create SRT file as ffmpeg's pre-commands(set-up commmands).
 			ffmpeg.AddPreCmd(createCmd)
 
-			/*
-				removeCmd, err := ffcmd.NewRemoveOneSubSRTCmd(srtFile)
-				if err != nil {
-					log.Printf("ffcmd.NewRemoveOneSubSRTCmd() error: %v", err)
-					return
-				}
-				// Add command to remove created file as ffmpeg's post-commands(clean-up commands).
-				ffmpeg.AddPostCmd(removeCmd)
-			*/
+			removeCmd, err := ffcmd.NewRemoveOneSubSRTCmd(srtFile)
+			if err != nil {
+				log.Printf("ffcmd.NewRemoveOneSubSRTCmd() error: %v", err)
+				return
+			}
+			// Add command to remove created file as ffmpeg's post-commands(clean-up commands).
+			ffmpeg.AddPostCmd(removeCmd)
 
 			// Create and chain subtitles filter.
 			subtitles := fmt.Sprintf("subtitles='%s':force_style='Fontsize=%d'", srtFile, c.FontSize)
@@ -352,7 +350,7 @@ func Example() {
 	log.Printf("ffmpeg.Run() succeeded")
 
 	// Output:
-	// echo -ne "1\n00:00:00,000 --> 00:00:03,000\nGood Times with Maomi & Mimao" > "op.srt" && echo -ne "1\n00:00:00,000 --> 00:00:03,000\nMimao likes lying on father's bed...😂\nMusic by penguinmusic: Better Day" > "ed.srt" && echo -ne "1\n00:00:00,000 --> 00:00:05,000\nMido's tickling Mimao and he's enjoying..." > "01.srt" && ffprobe -v error -select_streams v:0 -show_entries stream=duration -of csv=s=,:p=0 "02.MOV" | awk -F. '{ print $1 }' | read sec; hh=$((sec / 3600)); mm=$((sec % 3600 / 60)); ss=$((sec % 3600 % 60)); printf -v end "%02d:%02d:%02d,000" hh mm ss; echo -ne "1\n00:00:00,000 --> $end\nMimao's playing the toy." > "02.srt" && echo -ne "1\n00:00:01,000 --> 00:00:09,000\nIt's hard to brush Maomi's teeth..." > "03.srt" && echo "y" | ffmpeg \
+	// echo -ne "1\n00:00:00,000 --> 00:00:03,000\nGood Times with Maomi & Mimao" > "op.srt" && echo -ne "1\n00:00:00,000 --> 00:00:03,000\nMimao likes lying on father's bed...😂\nMusic by penguinmusic: Better Day" > "ed.srt" && echo -ne "1\n00:00:00,000 --> 00:00:05,000\nMido's tickling Mimao and he's enjoying..." > "01.srt" && end=$(ffprobe -v error -select_streams v:0 -show_entries stream=duration -of csv=p=0 "02.MOV" | awk '{ ms = int($1 * 1000 + 0.5); printf "%02d:%02d:%02d,%03d", ms / 3600000, ms / 60000 % 60, ms / 1000 % 60, ms % 1000 }'); echo -ne "1\n00:00:00,000 --> $end\nMimao's playing the toy." > "02.srt" && echo -ne "1\n00:00:01,000 --> 00:00:09,000\nIt's hard to brush Maomi's teeth..." > "03.srt" && echo "y" | ffmpeg \
 	// -i "op.jpg" \
 	// -i "ed.jpg" \
 	// -i "01.MP4" \
@@ -375,3 +373,111 @@ func Example() {
 	// -map "[outv]" \
 	// output.mp4 && rm "op.srt" && rm "ed.srt" && rm "01.srt" && rm "02.srt" && rm "03.srt"
 }
+
+func ExampleNewSplitFilterChain() {
+	ffmpeg := ffcmd.New("output.mp4", true)
+
+	// Scale the video once.
+	scaled := ffcmd.NewFilterChain("[scaled]")
+	scaled.AddInputByID(ffmpeg.AddInput("input.mp4"), "v", 0)
+	scaled.Chain("scale=640:-2")
+
+	// Split the scaled video to use it twice.
+	split, err := ffcmd.NewSplitFilterChain(scaled, 0, "v", 2)
+	if err != nil {
+		fmt.Printf("ffcmd.NewSplitFilterChain() error: %v", err)
+		return
+	}
+
+	// Flip the second output of split filterchain.
+	flipped := ffcmd.NewFilterChain("[flipped]")
+	flipped.AddInputByOutput(split, 1)
+	flipped.Chain("hflip")
+
+	// Stack the first output of split filterchain and the flipped video.
+	stack := ffcmd.NewFilterChain("[outv]")
+	stack.AddInputByOutput(split, 0)
+	stack.AddInputByOutput(flipped, 0)
+	stack.Chain("hstack=inputs=2")
+
+	ffmpeg.Chain(scaled).Chain(split).Chain(flipped).Chain(stack)
+
+	str, err := ffmpeg.String()
+	if err != nil {
+		fmt.Printf("ffmpeg.String() error: %v", err)
+		return
+	}
+
+	fmt.Println(str)
+
+	// Output:
+	// echo "y" | ffmpeg \
+	// -i "input.mp4" \
+	// -filter_complex " \
+	// [0:v:0]scale=640:-2[scaled];
+	// [scaled]split=2[scaled_0][scaled_1];
+	// [scaled_1]hflip[flipped];
+	// [scaled_0][flipped]hstack=inputs=2[outv]" \
+	// -map "[outv]" \
+	// output.mp4
+}
+
+func ExampleFFmpeg_String() {
+	ffmpeg := ffcmd.New("output.mp4", true)
+
+	fc := ffcmd.NewFilterChain("[outv]")
+	fc.AddInputByID(ffmpeg.AddInput("input.mp4"), "v", 0)
+
+	// The filter contains "$", "`", `"` and "\" which are special in double quotes of bash.
+	// They are escaped in the command and bash passes the filter to ffmpeg as it is.
+	fc.Chain("drawtext=text=Only $5\\, \"VIP\" `users`:fontsize=36")
+	ffmpeg.Chain(fc)
+
+	str, err := ffmpeg.String()
+	if err != nil {
+		fmt.Printf("ffmpeg.String() error: %v", err)
+		return
+	}
+
+	fmt.Println(str)
+
+	// Output:
+	// echo "y" | ffmpeg \
+	// -i "input.mp4" \
+	// -filter_complex " \
+	// [0:v:0]drawtext=text=Only \$5\\, \"VIP\" \`users\`:fontsize=36[outv]" \
+	// -map "[outv]" \
+	// output.mp4
+}
+
+func ExampleFFmpeg_MapByID() {
+	ffmpeg := ffcmd.New("output.mp4", true)
+	id := ffmpeg.AddInput("input.mp4")
+
+	fc := ffcmd.NewFilterChain("[outv]")
+	fc.AddInputByID(id, "v", 0)
+	fc.Chain("scale=1280:-2")
+	ffmpeg.Chain(fc)
+
+	// Map the output of filterchain by the label and the audio stream of input by the stream specifier.
+	// Stream specifiers of inputs are not in brackets(-map "0:a:0"), brackets are for labels of filtergraph only.
+	ffmpeg.MapByOutput(fc, 0)
+	ffmpeg.MapByID(id, "a", 0)
+
+	str, err := ffmpeg.String()
+	if err != nil {
+		fmt.Printf("ffmpeg.String() error: %v", err)
+		return
+	}
+
+	fmt.Println(str)
+
+	// Output:
+	// echo "y" | ffmpeg \
+	// -i "input.mp4" \
+	// -filter_complex " \
+	// [0:v:0]scale=1280:-2[outv]" \
+	// -map "0:a:0" \
+	// -map "[outv]" \
+	// output.mp4
+}
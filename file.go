@@ -0,0 +1,69 @@
+package ffcmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CreateFileCmd is the command to create a text file with the content.
+// It can be used to create side files(e.g. text file of drawtext filter) as ffmpeg's pre-commands.
+type CreateFileCmd struct {
+	file    string
+	content string
+}
+
+// NewCreateFileCmd returns a new command to create a text file.
+// file: filename of the file to create.
+// content: content of the file. It's written as it is.
+func NewCreateFileCmd(file, content string) (*CreateFileCmd, error) {
+	if file == "" {
+		return nil, fmt.Errorf("empty file name")
+	}
+
+	return &CreateFileCmd{file: file, content: content}, nil
+}
+
+// String returns the command string to run.
+func (cmd *CreateFileCmd) String() (string, error) {
+	// Quote content with single quotes to write it literally.
+	content := strings.ReplaceAll(cmd.content, `'`, `'\''`)
+	return fmt.Sprintf(`printf '%%s' '%s' > "%s"`, content, cmd.file), nil
+}
+
+func (cmd *CreateFileCmd) Run(dir string, fn ReadOutputFunc) error {
+	str, err := cmd.String()
+	if err != nil {
+		return fmt.Errorf("cmd.String() error: %v", err)
+	}
+
+	return RunCmd(dir, str, fn)
+}
+
+// RemoveFileCmd represents the command to remove a file.
+type RemoveFileCmd struct {
+	file string
+}
+
+// NewRemoveFileCmd returns a command to remove a file.
+// file: filename of the file to remove.
+func NewRemoveFileCmd(file string) (*RemoveFileCmd, error) {
+	if file == "" {
+		return nil, fmt.Errorf("empty file name")
+	}
+
+	return &RemoveFileCmd{file: file}, nil
+}
+
+// String returns the command string to run.
+func (cmd *RemoveFileCmd) String() (string, error) {
+	return fmt.Sprintf(`rm "%s"`, cmd.file), nil
+}
+
+func (cmd *RemoveFileCmd) Run(dir string, fn ReadOutputFunc) error {
+	str, err := cmd.String()
+	if err != nil {
+		return fmt.Errorf("cmd.String() error: %v", err)
+	}
+
+	return RunCmd(dir, str, fn)
+}
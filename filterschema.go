@@ -0,0 +1,511 @@
+package ffcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DynamicPads means the number of pads depends on the options(e.g. concat filter).
+const DynamicPads = -1
+
+// FilterOption represents an option of filter in the schema.
+type FilterOption struct {
+	// Name is the name of option.
+	Name string
+	// Aliases are the other names of option(e.g. "width" for "w" of scale filter).
+	Aliases []string
+	// Type is the type of option(e.g. "int", "double", "string", "boolean", "flags").
+	Type string
+	// HasRange indicates Min and Max are set.
+	HasRange bool
+	Min      float64
+	Max      float64
+	// Default is the default value.
+	Default string
+	// Constants are the named values of option(e.g. "disable", "decrease", "increase" of force_original_aspect_ratio).
+	Constants []string
+	// Description is the description of option.
+	Description string
+}
+
+// FilterSchema represents the schema of filter parsed from the output of "ffmpeg -h filter=NAME".
+type FilterSchema struct {
+	// Name is the name of filter.
+	Name string
+	// Description is the description of filter.
+	Description string
+	// Inputs, Outputs are the numbers of input / output pads. DynamicPads means it depends on the options.
+	Inputs  int
+	Outputs int
+	// Timeline indicates the filter supports "enable" option.
+	Timeline bool
+	// Options are the options in order. The options can be set by position in the order.
+	Options []FilterOption
+}
+
+var (
+	filterHelpRegexp     = regexp.MustCompile(`^Filter (\S+)`)
+	padRegexp            = regexp.MustCompile(`^\s+#\d+:`)
+	optionHelpRegexp     = regexp.MustCompile(`^ {2,3}(\S+)\s+<(\w+)>\s+(\S+)\s?(.*)$`)
+	constantHelpRegexp   = regexp.MustCompile(`^ {4,}(\S+)\s+(?:\S+\s+)?[.A-Z]{8,}\s?(.*)$`)
+	rangeRegexp          = regexp.MustCompile(`\(from (\S+) to (\S+)\)`)
+	defaultRegexp        = regexp.MustCompile(`\(default (.*)\)$`)
+	descriptionTrimRegex = regexp.MustCompile(`\s*\((from \S+ to \S+|default .*)\)`)
+)
+
+// limits are the names of limits printed by ffmpeg for the ranges of options.
+var limits = map[string]float64{
+	"INT_MAX":    math.MaxInt32,
+	"INT_MIN":    math.MinInt32,
+	"UINT32_MAX": math.MaxUint32,
+	"I64_MAX":    math.MaxInt64,
+	"I64_MIN":    math.MinInt64,
+	"UINT64_MAX": math.MaxUint64,
+	"FLT_MAX":    math.MaxFloat32,
+	"-FLT_MAX":   -math.MaxFloat32,
+	"FLT_MIN":    math.SmallestNonzeroFloat32,
+	"DBL_MAX":    math.MaxFloat64,
+	"-DBL_MAX":   -math.MaxFloat64,
+	"DBL_MIN":    math.SmallestNonzeroFloat64,
+}
+
+// parseLimit parses the min / max value of the range of option.
+func parseLimit(str string) (float64, bool) {
+	if v, ok := limits[str]; ok {
+		return v, true
+	}
+
+	v, err := strconv.ParseFloat(str, 64)
+	return v, err == nil
+}
+
+// isAlias returns if opt is an alias of prev.
+func isAlias(prev, opt *FilterOption) bool {
+	return prev.Type == opt.Type &&
+		prev.Description == opt.Description &&
+		prev.HasRange == opt.HasRange &&
+		prev.Min == opt.Min &&
+		prev.Max == opt.Max &&
+		prev.Default == opt.Default
+}
+
+// ParseFilterHelp parses the output of "ffmpeg -hide_banner -h filter=NAME" and returns the schema of filter.
+func ParseFilterHelp(output string) (*FilterSchema, error) {
+	lines := strings.Split(output, "\n")
+
+	fs := &FilterSchema{}
+	// Section of the line: "inputs", "outputs" or "options".
+	section := ""
+	var last *FilterOption
+	// aliased indicates the current option is an alias of last.
+	aliased := false
+
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \r")
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case fs.Name == "":
+			if m := filterHelpRegexp.FindStringSubmatch(line); m != nil {
+				fs.Name = m[1]
+				if i+1 < len(lines) {
+					fs.Description = strings.TrimSpace(lines[i+1])
+				}
+			}
+			continue
+		case trimmed == "Inputs:":
+			section = "inputs"
+			continue
+		case trimmed == "Outputs:":
+			section = "outputs"
+			continue
+		case strings.HasSuffix(trimmed, "AVOptions:"):
+			section = "options"
+			continue
+		case strings.Contains(line, "support for timeline"):
+			fs.Timeline = true
+			continue
+		}
+
+		switch section {
+		case "inputs", "outputs":
+			pads := &fs.Inputs
+			if section == "outputs" {
+				pads = &fs.Outputs
+			}
+
+			switch {
+			case padRegexp.MatchString(line):
+				if *pads != DynamicPads {
+					*pads++
+				}
+			case strings.HasPrefix(trimmed, "dynamic"):
+				*pads = DynamicPads
+			}
+		case "options":
+			if m := optionHelpRegexp.FindStringSubmatch(line); m != nil {
+				desc := m[4]
+				opt := FilterOption{Name: m[1], Type: m[2], Description: strings.TrimSpace(descriptionTrimRegex.ReplaceAllString(desc, ""))}
+
+				if r := rangeRegexp.FindStringSubmatch(desc); r != nil {
+					lower, ok1 := parseLimit(r[1])
+					upper, ok2 := parseLimit(r[2])
+					if ok1 && ok2 {
+						opt.HasRange, opt.Min, opt.Max = true, lower, upper
+					}
+				}
+
+				if d := defaultRegexp.FindStringSubmatch(desc); d != nil {
+					opt.Default = strings.Trim(d[1], `"`)
+				}
+
+				// ffmpeg prints an alias(e.g. "width" for "w") as another option right after the option.
+				// The help has no other hint, so an option is treated as an alias of the previous one
+				// only if the type, description, range and default are all the same.
+				// Options sharing only the description(e.g. "min" and "max" with different defaults) are kept.
+				aliased = last != nil && isAlias(last, &opt)
+				if aliased {
+					last.Aliases = append(last.Aliases, opt.Name)
+					continue
+				}
+
+				fs.Options = append(fs.Options, opt)
+				last = &fs.Options[len(fs.Options)-1]
+				continue
+			}
+
+			// Constants of an alias are the same as the option's.
+			if m := constantHelpRegexp.FindStringSubmatch(line); m != nil && last != nil && !aliased {
+				last.Constants = append(last.Constants, m[1])
+			}
+		}
+	}
+
+	if fs.Name == "" {
+		return nil, fmt.Errorf("no filter in the output")
+	}
+
+	return fs, nil
+}
+
+// Option returns the option by name or alias.
+func (fs *FilterSchema) Option(name string) (*FilterOption, bool) {
+	for i := range fs.Options {
+		opt := &fs.Options[i]
+		if opt.Name == name {
+			return opt, true
+		}
+
+		for _, alias := range opt.Aliases {
+			if alias == name {
+				return opt, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
+
+// suggest returns the option name closest to the unknown name, or empty string if none is close enough.
+func (fs *FilterSchema) suggest(name string) string {
+	best, bestDistance := "", len(name)/2+1
+
+	for _, opt := range fs.Options {
+		for _, n := range append([]string{opt.Name}, opt.Aliases...) {
+			if d := editDistance(name, n); d < bestDistance {
+				best, bestDistance = n, d
+			}
+		}
+	}
+
+	return best
+}
+
+// identRegexp matches the values which are names instead of numbers or expressions.
+var identRegexp = regexp.MustCompile(`^[A-Za-z_]\w*$`)
+
+// validateValue checks if the value is valid for the option.
+// Numeric options accept numbers, named constants and expressions. Only numbers and names are checked.
+func (opt *FilterOption) validateValue(value string) error {
+	isConstant := func(v string) bool {
+		for _, c := range opt.Constants {
+			if c == v {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch opt.Type {
+	case "int", "int64", "uint64", "float", "double":
+		if isConstant(value) {
+			return nil
+		}
+
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			if identRegexp.MatchString(value) && len(opt.Constants) > 0 {
+				return fmt.Errorf("invalid value %q, valid constants: %s", value, strings.Join(opt.Constants, ", "))
+			}
+			return nil
+		}
+
+		if opt.HasRange && (v < opt.Min || v > opt.Max) {
+			return fmt.Errorf("value %s out of range [%g, %g]", value, opt.Min, opt.Max)
+		}
+	case "boolean":
+		switch strings.ToLower(value) {
+		case "true", "false", "1", "0", "yes", "no", "y", "n", "auto", "-1":
+		default:
+			return fmt.Errorf("invalid boolean value %q", value)
+		}
+	case "flags":
+		for _, flag := range strings.FieldsFunc(value, func(r rune) bool { return r == '+' || r == '-' }) {
+			if len(opt.Constants) > 0 && !isConstant(flag) {
+				if _, err := strconv.ParseFloat(flag, 64); err != nil {
+					return fmt.Errorf("invalid flag %q, valid flags: %s", flag, strings.Join(opt.Constants, ", "))
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateFilter checks if the options of filter(e.g. "scale=720:960:force_original_aspect_ratio=decrease") match the schema.
+// It reports all problems at once by errors.Join().
+func (fs *FilterSchema) ValidateFilter(filter string) error {
+	return errors.Join(fs.validateFilter(filter)...)
+}
+
+// validateFilter returns all problems of the options of filter.
+func (fs *FilterSchema) validateFilter(filter string) []error {
+	name, opts := parseFilter(filter)
+	name, _, _ = strings.Cut(name, "@")
+
+	if name != fs.Name {
+		return []error{fmt.Errorf("filter %q does not match the schema of %q", name, fs.Name)}
+	}
+
+	var errs []error
+	for i, o := range opts {
+		var opt *FilterOption
+
+		if o.key == "" {
+			// Options set by position should be before the options set by name.
+			if i >= len(fs.Options) {
+				errs = append(errs, fmt.Errorf("too many options set by position: %d, max: %d", i+1, len(fs.Options)))
+				continue
+			}
+			opt = &fs.Options[i]
+		} else {
+			if o.key == "enable" && fs.Timeline {
+				continue
+			}
+
+			var ok bool
+			if opt, ok = fs.Option(o.key); !ok {
+				if s := fs.suggest(o.key); s != "" {
+					errs = append(errs, fmt.Errorf("unknown option %q, did you mean %q?", o.key, s))
+				} else {
+					errs = append(errs, fmt.Errorf("unknown option %q", o.key))
+				}
+				continue
+			}
+		}
+
+		if err := opt.validateValue(o.value); err != nil {
+			errs = append(errs, fmt.Errorf("option %q: %v", opt.Name, err))
+		}
+	}
+
+	return errs
+}
+
+// ValidatePads checks if the numbers of inputs / outputs connected to the filter match the pads of the schema.
+// Negative inputs / outputs are not checked.
+func (fs *FilterSchema) ValidatePads(inputs, outputs int) error {
+	if fs.Inputs != DynamicPads && inputs >= 0 && inputs != fs.Inputs {
+		return fmt.Errorf("%d inputs connected, filter has %d input pads", inputs, fs.Inputs)
+	}
+
+	if fs.Outputs != DynamicPads && outputs >= 0 && outputs != fs.Outputs {
+		return fmt.Errorf("%d outputs connected, filter has %d output pads", outputs, fs.Outputs)
+	}
+
+	return nil
+}
+
+// Validate checks the options of filters and the numbers of input / output pads by the schemas of filters.
+// The first filter takes the inputs of filterchain and the last filter outputs the outputs of filterchain.
+// Other filters are connected one by one. Unlabeled inputs / outputs are bound by ffmpeg and not checked.
+// It reports all problems at once by errors.Join().
+// Chain() does not validate the filters, call Validate() or FFmpeg.ValidateFilterOptions() explicitly.
+// schemas: schemas of filters by the names. Use ParseFilterHelp() or DiscoverFilterSchema() to get them.
+// Filters with nil schemas are skipped(e.g. failed to discover the schema).
+func (fc *FilterChain) Validate(schemas map[string]*FilterSchema) error {
+	var errs []error
+
+	for i, filter := range fc.filters {
+		name := filterName(filter)
+
+		fs, ok := schemas[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("filter #%d %q: no schema", i, name))
+			continue
+		}
+
+		if fs == nil {
+			continue
+		}
+
+		for _, err := range fs.validateFilter(filter) {
+			errs = append(errs, fmt.Errorf("filter #%d %q: %w", i, name, err))
+		}
+
+		inputs, outputs := 1, 1
+		if i == 0 {
+			inputs = len(fc.inputs)
+			if inputs == 0 {
+				inputs = -1
+			}
+		}
+		if i == len(fc.filters)-1 {
+			outputs = len(fc.outputs)
+			if outputs == 0 {
+				outputs = -1
+			}
+		}
+
+		if err := fs.ValidatePads(inputs, outputs); err != nil {
+			errs = append(errs, fmt.Errorf("filter #%d %q: %w", i, name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+var (
+	schemasMu    sync.Mutex
+	schemasCache = make(map[string]*FilterSchema)
+)
+
+// DiscoverFilterSchema runs "ffmpeg -h filter=NAME" to get the schema of filter. The result is cached per binary and filter.
+// ctx: context to cancel ffmpeg.
+// binary: name or path of ffmpeg binary. Empty means "ffmpeg" in PATH.
+// name: name of filter.
+func DiscoverFilterSchema(ctx context.Context, binary, name string) (*FilterSchema, error) {
+	if binary == "" {
+		binary = "ffmpeg"
+	}
+
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return nil, fmt.Errorf("exec.LookPath() error: %w", err)
+	}
+
+	key := path + "|" + name
+
+	schemasMu.Lock()
+	fs, ok := schemasCache[key]
+	schemasMu.Unlock()
+
+	if ok {
+		return fs, nil
+	}
+
+	// Run ffmpeg without holding the lock so that discovering a filter does not block the others and cache hits.
+	out, err := runBinary(ctx, path, "-h", "filter="+name)
+	if err != nil {
+		return nil, err
+	}
+
+	// ffmpeg prints "Unknown filter" and exits with 0 for unknown filters.
+	fs, err = ParseFilterHelp(out)
+	if err != nil {
+		return nil, fmt.Errorf("ParseFilterHelp() error: %v", err)
+	}
+
+	schemasMu.Lock()
+	defer schemasMu.Unlock()
+
+	// Keep the result stored by another goroutine discovering the same filter at the same time.
+	if cached, ok := schemasCache[key]; ok {
+		return cached, nil
+	}
+
+	schemasCache[key] = fs
+	return fs, nil
+}
+
+// ValidateFilterOptions discovers the schemas of filters used by the ffmpeg command and its pre-commands and validates all the filterchains.
+// It reports all problems at once by errors.Join(), including the filters failed to discover the schemas.
+// ctx: context to cancel ffmpeg.
+// binary: name or path of ffmpeg binary. Empty means "ffmpeg" in PATH.
+func (ff *FFmpeg) ValidateFilterOptions(ctx context.Context, binary string) error {
+	schemas := make(map[string]*FilterSchema)
+	var errs []error
+
+	for _, cmd := range ff.preCmds {
+		if pre, ok := cmd.(*FFmpeg); ok {
+			if err := pre.ValidateFilterOptions(ctx, binary); err != nil {
+				errs = append(errs, fmt.Errorf("pre-command: %w", err))
+			}
+		}
+	}
+
+	for _, fc := range ff.fg {
+		for _, filter := range fc.filters {
+			name := filterName(filter)
+			if _, ok := schemas[name]; ok {
+				continue
+			}
+
+			// Filters failed to discover the schema are reported once and skipped by Validate().
+			fs, err := DiscoverFilterSchema(ctx, binary, name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("filter %q: %w", name, err))
+			}
+			schemas[name] = fs
+		}
+	}
+
+	for i, fc := range ff.fg {
+		if err := fc.Validate(schemas); err != nil {
+			errs = append(errs, fmt.Errorf("filterchain #%d: %w", i, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,118 @@
+package ffcmd_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+
+	"github.com/northbright/ffcmd"
+)
+
+func ExampleParseFilterHelp() {
+	// Output of "ffmpeg -hide_banner -h filter=scale"(truncated).
+	// Use ffcmd.DiscoverFilterSchema() to run ffmpeg and parse the output.
+	help := `Filter scale
+  Scale the input video size and/or convert the image format.
+    Inputs:
+       #0: default (video)
+    Outputs:
+       #0: default (video)
+scale AVOptions:
+   w                 <string>     ..FV.....T. Output video width
+   width             <string>     ..FV.....T. Output video width
+   h                 <string>     ..FV.....T. Output video height
+   height            <string>     ..FV.....T. Output video height
+   flags             <string>     ..FV....... Flags to pass to libswscale (default "")
+   interl            <boolean>    ..FV....... set interlacing (default false)
+   force_original_aspect_ratio <int>        ..FV....... decrease or increase w/h if necessary to keep the original AR (from 0 to 2) (default disable)
+     disable         0            ..FV.......
+     decrease        1            ..FV.......
+     increase        2            ..FV.......
+   force_divisible_by <int>        ..FV....... enforce that the output resolution is divisible by a defined integer when force_original_aspect_ratio is used (from 1 to 256) (default 1)
+
+This filter has support for timeline through the 'enable' option.`
+
+	fs, err := ffcmd.ParseFilterHelp(help)
+	if err != nil {
+		fmt.Printf("ffcmd.ParseFilterHelp() error: %v", err)
+		return
+	}
+
+	fmt.Printf("name: %s, inputs: %d, outputs: %d, timeline: %v\n", fs.Name, fs.Inputs, fs.Outputs, fs.Timeline)
+	for _, opt := range fs.Options {
+		fmt.Printf("%s %v <%s> range: %v [%g, %g], default: %q, constants: %v\n", opt.Name, opt.Aliases, opt.Type, opt.HasRange, opt.Min, opt.Max, opt.Default, opt.Constants)
+	}
+
+	// Options with the same description are aliases only if the range and default are also the same.
+	// "t" is an alias of "type" while "max" is not an alias of "min".
+	help = `Filter example
+  Example filter.
+example AVOptions:
+   type              <int>        ..FV.....T. set the fade direction (from 0 to 1) (default in)
+     in              0            ..FV.....T. fade-in
+     out             1            ..FV.....T. fade-out
+   t                 <int>        ..FV.....T. set the fade direction (from 0 to 1) (default in)
+     in              0            ..FV.....T. fade-in
+     out             1            ..FV.....T. fade-out
+   min               <int>        ..FV....... set the threshold (from 0 to 255) (default 0)
+   max               <int>        ..FV....... set the threshold (from 0 to 255) (default 255)`
+
+	example, err := ffcmd.ParseFilterHelp(help)
+	if err != nil {
+		fmt.Printf("ffcmd.ParseFilterHelp() error: %v", err)
+		return
+	}
+
+	for _, opt := range example.Options {
+		fmt.Printf("%s %v <%s> range: %v [%g, %g], default: %q, constants: %v\n", opt.Name, opt.Aliases, opt.Type, opt.HasRange, opt.Min, opt.Max, opt.Default, opt.Constants)
+	}
+
+	schemas := map[string]*ffcmd.FilterSchema{fs.Name: fs}
+
+	// Valid filterchain.
+	fc := ffcmd.NewFilterChain("[outv]")
+	fc.AddInputByID(0, "v", 0)
+	fc.Chain("scale=width=1280:h=-2:force_original_aspect_ratio=decrease:enable='gte(t,1)'")
+
+	fmt.Printf("fc.Validate(): %v\n", fc.Validate(schemas))
+
+	// Invalid filterchain with typo, out of range value, unknown constant and 2 inputs.
+	fc = ffcmd.NewFilterChain("[outv]")
+	fc.AddInputByID(0, "v", 0)
+	fc.AddInputByID(1, "v", 0)
+	fc.Chain("scale=1280:720:force_divisable_by=2").Chain("scale=force_divisible_by=512:force_original_aspect_ratio=shrink")
+
+	fmt.Printf("fc.Validate() error:\n%v\n", fc.Validate(schemas))
+
+	// FFmpeg.ValidateFilterOptions() discovers the schemas by running ffmpeg.
+	// It reports the failures of all filters instead of stopping at the first one.
+	ff := ffcmd.New("output.mp4", true)
+	fc = ffcmd.NewFilterChain("[outv]")
+	fc.AddInputByID(ff.AddInput("input.mp4"), "v", 0)
+	fc.Chain("scale=1280:-2").Chain("hflip")
+	ff.Chain(fc)
+
+	// The error message of exec.LookPath() differs by platform, check the cause only.
+	err = ff.ValidateFilterOptions(context.Background(), "ffmpeg-not-found")
+	fmt.Printf("errors.Is(err, exec.ErrNotFound): %v\n", errors.Is(err, exec.ErrNotFound))
+
+	// Output:
+	// name: scale, inputs: 1, outputs: 1, timeline: true
+	// w [width] <string> range: false [0, 0], default: "", constants: []
+	// h [height] <string> range: false [0, 0], default: "", constants: []
+	// flags [] <string> range: false [0, 0], default: "", constants: []
+	// interl [] <boolean> range: false [0, 0], default: "false", constants: []
+	// force_original_aspect_ratio [] <int> range: true [0, 2], default: "disable", constants: [disable decrease increase]
+	// force_divisible_by [] <int> range: true [1, 256], default: "1", constants: []
+	// type [t] <int> range: true [0, 1], default: "in", constants: [in out]
+	// min [] <int> range: true [0, 255], default: "0", constants: []
+	// max [] <int> range: true [0, 255], default: "255", constants: []
+	// fc.Validate(): <nil>
+	// fc.Validate() error:
+	// filter #0 "scale": unknown option "force_divisable_by", did you mean "force_divisible_by"?
+	// filter #0 "scale": 2 inputs connected, filter has 1 input pads
+	// filter #1 "scale": option "force_divisible_by": value 512 out of range [1, 256]
+	// filter #1 "scale": option "force_original_aspect_ratio": invalid value "shrink", valid constants: disable, decrease, increase
+	// errors.Is(err, exec.ErrNotFound): true
+}
@@ -0,0 +1,117 @@
+package ffcmd
+
+import (
+	"fmt"
+)
+
+// FitMode represents the mode to fit video into the target resolution.
+type FitMode int
+
+const (
+	// FitPad scales the video to fit in the resolution and pads with color(letterbox / pillarbox).
+	FitPad FitMode = iota
+	// FitCrop scales the video to fill the resolution and crops the overflow.
+	FitCrop
+	// FitStretch scales the video to the resolution without keeping the aspect ratio.
+	FitStretch
+	// FitBlur fits the video in the resolution over a blurred and cropped copy of itself as background(social-media style).
+	FitBlur
+)
+
+// Fit represents the options to fit video into the target resolution.
+type Fit struct {
+	// W, H are the target width and height.
+	W int
+	H int
+	// Mode is the fit mode.
+	Mode FitMode
+	// PadColor is the color to pad for FitPad(e.g. "black", "white", "#336699"). Empty means default(black).
+	PadColor string
+	// BlurSigma is the sigma of gaussian blur of the background for FitBlur. 0 means default(20).
+	BlurSigma int
+}
+
+// filters returns the filters to fit video for the modes which need only one filterchain.
+func (f *Fit) filters() ([]string, error) {
+	switch f.Mode {
+	case FitPad:
+		pad := fmt.Sprintf("pad=%d:%d:(ow-iw)/2:(oh-ih)/2", f.W, f.H)
+		if f.PadColor != "" {
+			pad += ":color=" + f.PadColor
+		}
+
+		return []string{
+			fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=decrease", f.W, f.H),
+			pad,
+		}, nil
+	case FitCrop:
+		return []string{
+			fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=increase", f.W, f.H),
+			fmt.Sprintf("crop=%d:%d", f.W, f.H),
+		}, nil
+	case FitStretch:
+		return []string{
+			fmt.Sprintf("scale=%d:%d", f.W, f.H),
+		}, nil
+	case FitBlur:
+		return nil, fmt.Errorf("blurred-background fill requires multiple filterchains")
+	default:
+		return nil, fmt.Errorf("unsupported fit mode: %d", f.Mode)
+	}
+}
+
+// FilterChains returns the filterchains to fit the video into the target resolution.
+// in, inID: filterchain of the video and the 0-based index of its output.
+// output: output label of the fitted video in the "[OUTPUT_LABEL]" format.
+// The last filterchain outputs the fitted video.
+func (f *Fit) FilterChains(in *FilterChain, inID int, output string) ([]*FilterChain, error) {
+	if f.W <= 0 || f.H <= 0 {
+		return nil, fmt.Errorf("invalid resolution")
+	}
+
+	if f.Mode != FitBlur {
+		filters, err := f.filters()
+		if err != nil {
+			return nil, err
+		}
+
+		fc := NewFilterChain(output)
+		fc.AddInputByOutput(in, inID)
+		for _, filter := range filters {
+			fc.Chain(filter)
+		}
+		return []*FilterChain{fc}, nil
+	}
+
+	sigma := f.BlurSigma
+	if sigma <= 0 {
+		sigma = 20
+	}
+
+	name := labelName(output)
+
+	// Use the video twice as background and foreground.
+	split, err := NewSplitFilterChain(in, inID, "v", 2)
+	if err != nil {
+		return nil, fmt.Errorf("NewSplitFilterChain() error: %v", err)
+	}
+
+	bg := NewFilterChain(fmt.Sprintf("[%s_bg]", name))
+	bg.AddInputByOutput(split, 0)
+	bg.Chain(fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=increase", f.W, f.H))
+	bg.Chain(fmt.Sprintf("crop=%d:%d", f.W, f.H))
+	bg.Chain(fmt.Sprintf("gblur=sigma=%d", sigma))
+
+	fg := NewFilterChain(fmt.Sprintf("[%s_fg]", name))
+	fg.AddInputByOutput(split, 1)
+	fg.Chain(fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=decrease", f.W, f.H))
+
+	o := &Overlay{Anchor: AnchorCenter}
+	overlayFCs, err := o.FilterChains(bg, 0, fg, 0, output)
+	if err != nil {
+		return nil, fmt.Errorf("o.FilterChains() error: %v", err)
+	}
+
+	fcs := []*FilterChain{split, bg, fg}
+	return append(fcs, overlayFCs...), nil
+}
@@ -0,0 +1,46 @@
+package ffcmd_test
+
+import (
+	"fmt"
+
+	"github.com/northbright/ffcmd"
+)
+
+func ExampleFit() {
+	ffmpeg := ffcmd.New("output.mp4", true)
+
+	in := ffcmd.NewFilterChain()
+	in.AddInputByID(ffmpeg.AddInput("landscape.mp4"), "v", 0)
+
+	// Export vertical(9:16) video from landscape footage with blurred background.
+	f := &ffcmd.Fit{W: 1080, H: 1920, Mode: ffcmd.FitBlur}
+
+	fcs, err := f.FilterChains(in, 0, "[outv]")
+	if err != nil {
+		fmt.Printf("f.FilterChains() error: %v", err)
+		return
+	}
+
+	for _, fc := range fcs {
+		ffmpeg.Chain(fc)
+	}
+
+	str, err := ffmpeg.String()
+	if err != nil {
+		fmt.Printf("ffmpeg.String() error: %v", err)
+		return
+	}
+
+	fmt.Println(str)
+
+	// Output:
+	// echo "y" | ffmpeg \
+	// -i "landscape.mp4" \
+	// -filter_complex " \
+	// [0:v:0]split=2[0_v_0_0][0_v_0_1];
+	// [0_v_0_0]scale=1080:1920:force_original_aspect_ratio=increase,crop=1080:1920,gblur=sigma=20[outv_bg];
+	// [0_v_0_1]scale=1080:1920:force_original_aspect_ratio=decrease[outv_fg];
+	// [outv_bg][outv_fg]overlay=x=(main_w-overlay_w)/2:y=(main_h-overlay_h)/2[outv]" \
+	// -map "[outv]" \
+	// output.mp4
+}
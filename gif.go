@@ -0,0 +1,252 @@
+package ffcmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Dither algorithms of paletteuse filter.
+const (
+	DitherBayer          = "bayer"
+	DitherHeckbert       = "heckbert"
+	DitherFloydSteinberg = "floyd_steinberg"
+	DitherSierra2        = "sierra2"
+	DitherSierra2_4a     = "sierra2_4a"
+	DitherNone           = "none"
+)
+
+// GIF represents the options to export the video as high-quality GIF by palettegen and paletteuse filters.
+type GIF struct {
+	// Start is the timestamp in the "HH:MM:SS(.mmm)" format to start. Empty means the beginning.
+	Start string
+	// Duration is the duration in seconds to export. 0 means to the end.
+	Duration float32
+	// FPS is the frame rate of GIF. 0 means default(10).
+	FPS int
+	// Width is the width of GIF. The height is scaled to keep the aspect ratio. 0 means the original size.
+	Width int
+	// MaxColors is the max number of colors of the palette(4 - 256). 0 means default(256).
+	MaxColors int
+	// Dither is the dither algorithm(e.g. DitherBayer). Empty means default(DitherSierra2_4a).
+	Dither string
+	// BayerScale is the scale of bayer dither(0 - 5). It's used for DitherBayer only.
+	BayerScale int
+	// Loop is the loop count. 0 means loops forever, -1 means plays once, N means repeats N times.
+	Loop int
+	// TwoPass generates the palette to a temp PNG file by a pre-command and removes it by a post-command.
+	// Otherwise, the palette is generated and used in a single command by split filter.
+	TwoPass bool
+}
+
+// animationInputOptions returns the input options to trim the video.
+func animationInputOptions(start string, duration float32) ([]string, error) {
+	var options []string
+
+	if start != "" {
+		ts, err := NewTimestamp(start)
+		if err != nil {
+			return nil, fmt.Errorf("NewTimestamp() error: %v", err)
+		}
+		options = append(options, "-ss", ts.String())
+	}
+
+	if duration < 0 {
+		return nil, fmt.Errorf("negative duration")
+	}
+
+	if duration > 0 {
+		options = append(options, "-t", formatSecond(duration))
+	}
+
+	return options, nil
+}
+
+// animationFilterChain returns the filterchain to set frame rate and scale the video.
+func animationFilterChain(inputID, fps, width int, output string) *FilterChain {
+	if fps <= 0 {
+		fps = 10
+	}
+
+	fc := NewFilterChain(output)
+	fc.AddInputByID(inputID, "v", 0)
+	fc.Chain(fmt.Sprintf("fps=%d", fps))
+
+	if width > 0 {
+		fc.Chain(fmt.Sprintf("scale=%d:-1:flags=lanczos", width))
+	}
+
+	return fc
+}
+
+// paletteGenFilter returns the palettegen filter.
+func (g *GIF) paletteGenFilter() (string, error) {
+	if g.MaxColors == 0 {
+		return "palettegen", nil
+	}
+
+	if g.MaxColors < 4 || g.MaxColors > 256 {
+		return "", fmt.Errorf("invalid max colors")
+	}
+
+	return fmt.Sprintf("palettegen=max_colors=%d", g.MaxColors), nil
+}
+
+// paletteUseFilter returns the paletteuse filter.
+func (g *GIF) paletteUseFilter() (string, error) {
+	dither := g.Dither
+	if dither == "" {
+		dither = DitherSierra2_4a
+	}
+
+	filter := "paletteuse=dither=" + dither
+
+	if dither == DitherBayer {
+		if g.BayerScale < 0 || g.BayerScale > 5 {
+			return "", fmt.Errorf("invalid bayer scale")
+		}
+		filter += fmt.Sprintf(":bayer_scale=%d", g.BayerScale)
+	}
+
+	return filter, nil
+}
+
+// paletteFileName returns the temp palette filename for the output(e.g. "output_palette.png" for "output.gif").
+func paletteFileName(output string) string {
+	return strings.TrimSuffix(output, filepath.Ext(output)) + "_palette.png"
+}
+
+// FFmpeg returns the ffmpeg command to export the video as GIF.
+// input: video file.
+// output: GIF file(e.g. "preview.gif").
+// overwrite: if overwrite output when run ffmpeg command.
+func (g *GIF) FFmpeg(input, output string, overwrite bool) (*FFmpeg, error) {
+	options, err := animationInputOptions(g.Start, g.Duration)
+	if err != nil {
+		return nil, err
+	}
+
+	if g.Loop < -1 {
+		return nil, fmt.Errorf("invalid loop count")
+	}
+
+	paletteGen, err := g.paletteGenFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	paletteUse, err := g.paletteUseFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	ff := New(output, overwrite)
+	id := ff.AddInputWithOptions(input, options...)
+
+	src := animationFilterChain(id, g.FPS, g.Width, "[gif_src]")
+	ff.Chain(src)
+
+	gif := NewFilterChain("[gif]")
+
+	if g.TwoPass {
+		palette := paletteFileName(output)
+
+		// Pass 1: generate the palette file.
+		paletteFF := New(palette, true)
+		paletteID := paletteFF.AddInputWithOptions(input, options...)
+		paletteFC := animationFilterChain(paletteID, g.FPS, g.Width, "[palette]")
+		paletteFC.Chain(paletteGen)
+		paletteFF.Chain(paletteFC)
+		// Write the only palette frame to the image file instead of an image sequence.
+		paletteFF.AddOutputOptions("-update", "1", "-frames:v", "1")
+
+		removeCmd, err := NewRemoveFileCmd(palette)
+		if err != nil {
+			return nil, fmt.Errorf("NewRemoveFileCmd() error: %v", err)
+		}
+
+		ff.AddPreCmd(paletteFF)
+		ff.AddPostCmd(removeCmd)
+
+		// Pass 2: use the palette file.
+		gif.AddInputByOutput(src, 0)
+		gif.AddInputByID(ff.AddInput(palette), "v", 0)
+	} else {
+		// Use the video twice to generate and use the palette.
+		split, err := NewSplitFilterChain(src, 0, "v", 2)
+		if err != nil {
+			return nil, fmt.Errorf("NewSplitFilterChain() error: %v", err)
+		}
+		ff.Chain(split)
+
+		paletteFC := NewFilterChain("[gif_palette]")
+		paletteFC.AddInputByOutput(split, 0)
+		paletteFC.Chain(paletteGen)
+		ff.Chain(paletteFC)
+
+		gif.AddInputByOutput(split, 1)
+		gif.AddInputByOutput(paletteFC, 0)
+	}
+
+	gif.Chain(paletteUse)
+	ff.Chain(gif)
+
+	ff.AddOutputOptions("-loop", fmt.Sprintf("%d", g.Loop))
+	return ff, nil
+}
+
+// WebP represents the options to export the video as animated WebP.
+type WebP struct {
+	// Start is the timestamp in the "HH:MM:SS(.mmm)" format to start. Empty means the beginning.
+	Start string
+	// Duration is the duration in seconds to export. 0 means to the end.
+	Duration float32
+	// FPS is the frame rate of WebP. 0 means default(10).
+	FPS int
+	// Width is the width of WebP. The height is scaled to keep the aspect ratio. 0 means the original size.
+	Width int
+	// Quality is the quality of lossy compression(0 - 100). 0 means default(75).
+	Quality int
+	// Lossless uses lossless compression. Quality is ignored.
+	Lossless bool
+	// Loop is the loop count. 0 means loops forever, N means plays N times.
+	Loop int
+}
+
+// FFmpeg returns the ffmpeg command to export the video as animated WebP.
+// input: video file.
+// output: WebP file(e.g. "preview.webp").
+// overwrite: if overwrite output when run ffmpeg command.
+func (w *WebP) FFmpeg(input, output string, overwrite bool) (*FFmpeg, error) {
+	options, err := animationInputOptions(w.Start, w.Duration)
+	if err != nil {
+		return nil, err
+	}
+
+	if w.Quality < 0 || w.Quality > 100 {
+		return nil, fmt.Errorf("invalid quality")
+	}
+
+	if w.Loop < 0 {
+		return nil, fmt.Errorf("invalid loop count")
+	}
+
+	ff := New(output, overwrite)
+	id := ff.AddInputWithOptions(input, options...)
+	ff.Chain(animationFilterChain(id, w.FPS, w.Width, "[webp]"))
+
+	ff.AddOutputOptions("-c:v", "libwebp")
+
+	if w.Lossless {
+		ff.AddOutputOptions("-lossless", "1")
+	} else {
+		quality := w.Quality
+		if quality == 0 {
+			quality = 75
+		}
+		ff.AddOutputOptions("-quality", fmt.Sprintf("%d", quality))
+	}
+
+	ff.AddOutputOptions("-loop", fmt.Sprintf("%d", w.Loop))
+	return ff, nil
+}
@@ -0,0 +1,87 @@
+package ffcmd_test
+
+import (
+	"fmt"
+
+	"github.com/northbright/ffcmd"
+)
+
+func ExampleGIF() {
+	// Generate and use the palette in a single command.
+	g := &ffcmd.GIF{Start: "00:00:10", Duration: 3, FPS: 15, Width: 480}
+
+	ff, err := g.FFmpeg("input.mp4", "preview.gif", true)
+	if err != nil {
+		fmt.Printf("g.FFmpeg() error: %v", err)
+		return
+	}
+
+	str, err := ff.String()
+	if err != nil {
+		fmt.Printf("ff.String() error: %v", err)
+		return
+	}
+	fmt.Println(str)
+
+	// Generate the palette file first with bayer dither and play once.
+	g = &ffcmd.GIF{Width: 320, MaxColors: 128, Dither: ffcmd.DitherBayer, BayerScale: 3, Loop: -1, TwoPass: true}
+
+	if ff, err = g.FFmpeg("input.mp4", "preview.gif", true); err != nil {
+		fmt.Printf("g.FFmpeg() error: %v", err)
+		return
+	}
+
+	if str, err = ff.String(); err != nil {
+		fmt.Printf("ff.String() error: %v", err)
+		return
+	}
+	fmt.Println(str)
+
+	// Animated WebP.
+	w := &ffcmd.WebP{Duration: 3, FPS: 15, Width: 480, Quality: 80}
+
+	if ff, err = w.FFmpeg("input.mp4", "preview.webp", true); err != nil {
+		fmt.Printf("w.FFmpeg() error: %v", err)
+		return
+	}
+
+	if str, err = ff.String(); err != nil {
+		fmt.Printf("ff.String() error: %v", err)
+		return
+	}
+	fmt.Println(str)
+
+	// Output:
+	// echo "y" | ffmpeg \
+	// -ss 00:00:10.000 -t 3.000 -i "input.mp4" \
+	// -filter_complex " \
+	// [0:v:0]fps=15,scale=480:-1:flags=lanczos[gif_src];
+	// [gif_src]split=2[gif_src_0][gif_src_1];
+	// [gif_src_0]palettegen[gif_palette];
+	// [gif_src_1][gif_palette]paletteuse=dither=sierra2_4a[gif]" \
+	// -map "[gif]" \
+	// -loop 0 \
+	// preview.gif
+	// echo "y" | ffmpeg \
+	// -i "input.mp4" \
+	// -filter_complex " \
+	// [0:v:0]fps=10,scale=320:-1:flags=lanczos,palettegen=max_colors=128[palette]" \
+	// -map "[palette]" \
+	// -update 1 -frames:v 1 \
+	// preview_palette.png && echo "y" | ffmpeg \
+	// -i "input.mp4" \
+	// -i "preview_palette.png" \
+	// -filter_complex " \
+	// [0:v:0]fps=10,scale=320:-1:flags=lanczos[gif_src];
+	// [gif_src][1:v:0]paletteuse=dither=bayer:bayer_scale=3[gif]" \
+	// -map "[gif]" \
+	// -loop -1 \
+	// preview.gif && rm "preview_palette.png"
+	// echo "y" | ffmpeg \
+	// -t 3.000 -i "input.mp4" \
+	// -filter_complex " \
+	// [0:v:0]fps=15,scale=480:-1:flags=lanczos[webp]" \
+	// -map "[webp]" \
+	// -c:v libwebp -quality 80 -loop 0 \
+	// preview.webp
+}
@@ -0,0 +1,444 @@
+package ffcmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// LoudnessTarget represents the target of EBU R128 loudness normalization.
+type LoudnessTarget struct {
+	// I is the integrated loudness in LUFS(-70.0 - -5.0).
+	I float32
+	// TP is the maximum true peak in dBTP(-9.0 - 0.0).
+	TP float32
+	// LRA is the loudness range in LU(1.0 - 50.0).
+	LRA float32
+}
+
+// Loudness targets of delivery platforms.
+var (
+	// LoudnessEBUR128 is for European broadcast(EBU R128).
+	LoudnessEBUR128 = LoudnessTarget{I: -23, TP: -1, LRA: 7}
+	// LoudnessATSCA85 is for US broadcast(ATSC A/85).
+	LoudnessATSCA85 = LoudnessTarget{I: -24, TP: -2, LRA: 7}
+	// LoudnessStreaming is for streaming platforms(e.g. YouTube, Spotify).
+	LoudnessStreaming = LoudnessTarget{I: -14, TP: -1, LRA: 11}
+	// LoudnessPodcast is for podcasts(e.g. Apple Podcasts).
+	LoudnessPodcast = LoudnessTarget{I: -16, TP: -1, LRA: 11}
+)
+
+// validate checks if the target is in the range of loudnorm filter.
+func (t *LoudnessTarget) validate() error {
+	if t.I < -70 || t.I > -5 {
+		return fmt.Errorf("invalid integrated loudness: %g", t.I)
+	}
+
+	if t.TP < -9 || t.TP > 0 {
+		return fmt.Errorf("invalid true peak: %g", t.TP)
+	}
+
+	if t.LRA < 1 || t.LRA > 50 {
+		return fmt.Errorf("invalid loudness range: %g", t.LRA)
+	}
+
+	return nil
+}
+
+// MeasureFilter returns the loudnorm filter to measure the loudness(pass 1).
+// It prints the measured values in JSON format to stderr.
+func (t *LoudnessTarget) MeasureFilter() (string, error) {
+	if err := t.validate(); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("loudnorm=I=%g:TP=%g:LRA=%g:print_format=json", t.I, t.TP, t.LRA), nil
+}
+
+// Filter returns the loudnorm filter to normalize the loudness in linear mode by the measured values(pass 2).
+func (t *LoudnessTarget) Filter(m *LoudnessMeasurement) (string, error) {
+	if err := t.validate(); err != nil {
+		return "", err
+	}
+
+	if m == nil {
+		return "", fmt.Errorf("nil measurement")
+	}
+
+	// Silent audio is measured as -inf.
+	for _, v := range []float64{m.InputI, m.InputTP, m.InputLRA, m.InputThresh, m.TargetOffset} {
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return "", fmt.Errorf("invalid measured values, the audio may be silent")
+		}
+	}
+
+	return fmt.Sprintf("loudnorm=I=%g:TP=%g:LRA=%g:measured_I=%.2f:measured_TP=%.2f:measured_LRA=%.2f:measured_thresh=%.2f:offset=%.2f:linear=true",
+		t.I, t.TP, t.LRA, m.InputI, m.InputTP, m.InputLRA, m.InputThresh, m.TargetOffset), nil
+}
+
+// Normalize chains the loudnorm filter in linear mode into the audio filterchain.
+// loudnorm filter upsamples the audio to 192 kHz, use Profile.NormalizeAudio() after it to set the sample rate.
+// The measured values must be of the same audio as the output of fc. Normalize each clip by the values of its own stream,
+// or measure the audio processed by the render(e.g. trimmed, mixed with BGM) at fc by MeasureRenderLoudness().
+// fc: audio filterchain.
+// m: measured values of the audio.
+func (t *LoudnessTarget) Normalize(fc *FilterChain, m *LoudnessMeasurement) error {
+	filter, err := t.Filter(m)
+	if err != nil {
+		return err
+	}
+
+	fc.Chain(filter)
+	return nil
+}
+
+// LoudnessMeasurement represents the measured values of loudnorm filter.
+type LoudnessMeasurement struct {
+	// InputI is the integrated loudness in LUFS.
+	InputI float64
+	// InputTP is the true peak in dBTP.
+	InputTP float64
+	// InputLRA is the loudness range in LU.
+	InputLRA float64
+	// InputThresh is the threshold in LUFS.
+	InputThresh float64
+	// TargetOffset is the offset gain in LU.
+	TargetOffset float64
+}
+
+// ParseLoudnormOutput parses the output(stderr) of ffmpeg with loudnorm filter in measurement mode and returns the measured values.
+func ParseLoudnormOutput(output string) (*LoudnessMeasurement, error) {
+	// Find the last JSON block printed by loudnorm filter.
+	i := strings.LastIndex(output, "[Parsed_loudnorm")
+	if i < 0 {
+		return nil, fmt.Errorf("no loudnorm output")
+	}
+
+	start := strings.Index(output[i:], "{")
+	if start < 0 {
+		return nil, fmt.Errorf("no JSON in loudnorm output")
+	}
+	start += i
+
+	end := strings.Index(output[start:], "}")
+	if end < 0 {
+		return nil, fmt.Errorf("incomplete JSON in loudnorm output")
+	}
+	end += start
+
+	var values struct {
+		InputI       string `json:"input_i"`
+		InputTP      string `json:"input_tp"`
+		InputLRA     string `json:"input_lra"`
+		InputThresh  string `json:"input_thresh"`
+		TargetOffset string `json:"target_offset"`
+	}
+
+	if err := json.Unmarshal([]byte(output[start:end+1]), &values); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal() error: %v", err)
+	}
+
+	m := &LoudnessMeasurement{}
+	fields := []struct {
+		name  string
+		str   string
+		value *float64
+	}{
+		{"input_i", values.InputI, &m.InputI},
+		{"input_tp", values.InputTP, &m.InputTP},
+		{"input_lra", values.InputLRA, &m.InputLRA},
+		{"input_thresh", values.InputThresh, &m.InputThresh},
+		{"target_offset", values.TargetOffset, &m.TargetOffset},
+	}
+
+	for _, f := range fields {
+		v, err := strconv.ParseFloat(strings.TrimSpace(f.str), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %q", f.name, f.str)
+		}
+		*f.value = v
+	}
+
+	return m, nil
+}
+
+// MeasureLoudness runs ffmpeg with loudnorm filter in measurement mode(pass 1) and returns the measured values of the audio stream.
+// It measures the raw audio stream of the input only. Use the values to normalize the audio of the input per clip,
+// or use MeasureRenderLoudness() to measure the audio processed by the filters of the render.
+// ctx: context to cancel ffmpeg.
+// dir: working dir to run ffmpeg.
+// input: media file.
+// streamID: index of the audio stream.
+// target: loudness target.
+func MeasureLoudness(ctx context.Context, dir, input string, streamID int, target *LoudnessTarget) (*LoudnessMeasurement, error) {
+	filter, err := target.MeasureFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := runFFmpeg(ctx, dir, "-i", input, "-map", fmt.Sprintf("0:a:%d", streamID), "-af", filter, "-f", "null", "-")
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseLoudnormOutput(string(out))
+}
+
+// audioFilterChain returns a copy of the filterchain to process the audio only.
+// It removes the video streams of concat filter with both video and audio(e.g. concat filter of Timeline),
+// so that the video is not decoded and filtered to measure the audio.
+func audioFilterChain(fc *FilterChain) *FilterChain {
+	c := &FilterChain{inputs: fc.inputs, outputs: fc.outputs, filters: fc.filters}
+	if len(fc.filters) != 1 {
+		return c
+	}
+
+	name, opts := parseFilter(fc.filters[0])
+	if name != "concat" {
+		return c
+	}
+
+	// Default values and positional order of the options of concat filter.
+	values := map[string]int{"n": 2, "v": 1, "a": 0}
+	keys := []string{"n", "v", "a"}
+	for i, opt := range opts {
+		key := opt.key
+		if key == "" && i < len(keys) {
+			key = keys[i]
+		}
+
+		if _, ok := values[key]; ok {
+			v, err := strconv.Atoi(opt.value)
+			if err != nil {
+				return c
+			}
+			values[key] = v
+		}
+	}
+
+	n, v, a := values["n"], values["v"], values["a"]
+	if v == 0 || a == 0 || len(fc.inputs) != n*(v+a) || len(fc.outputs) != v+a {
+		return c
+	}
+
+	// Streams of each segment are ordered as video streams then audio streams.
+	var inputs []any
+	for seg := 0; seg < n; seg++ {
+		inputs = append(inputs, fc.inputs[seg*(v+a)+v:(seg+1)*(v+a)]...)
+	}
+
+	c.inputs = inputs
+	c.outputs = fc.outputs[v:]
+	c.filters = []string{fmt.Sprintf("concat=n=%d:v=0:a=%d", n, a)}
+	return c
+}
+
+// inputStreamRegexp matches the input stream specifier in the "[INPUT_ID:STREAM]" format(e.g. "[0:a:0]").
+var inputStreamRegexp = regexp.MustCompile(`^\[(\d+)(:[^\]]*)?\]$`)
+
+// removedFiles returns the file removed by the command.
+func (cmd *RemoveFileCmd) removedFiles() []string {
+	return []string{cmd.file}
+}
+
+// removedFiles returns the SRT file removed by the command.
+func (cmd *RemoveOneSubSRTCmd) removedFiles() []string {
+	return []string{cmd.srtFile}
+}
+
+// MeasureFFmpeg returns the ffmpeg command to measure the loudness(pass 1) of the audio at the point of the render to chain loudnorm filter(pass 2).
+// It keeps the inputs, filterchains and pre / post commands(e.g. to create / remove a file used by a filter) which the audio depends on only,
+// and removes the video streams of concat filter, so the measured values match the audio to normalize without processing the video.
+// It decodes and filters the audio of the render once more before rendering.
+// Video is decoded only when the audio depends on filters taking video(e.g. the audio of a video generated by filters).
+// Measure it before calling Normalize() with fc.
+// ff: ffmpeg command to render the output.
+// fc, outputID: audio filterchain of the render and the 0-based index of its output to normalize.
+func (t *LoudnessTarget) MeasureFFmpeg(ff *FFmpeg, fc *FilterChain, outputID int) (*FFmpeg, error) {
+	filter, err := t.MeasureFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	label := fc.Output(outputID)
+	if label == "" {
+		return nil, fmt.Errorf("no output of filterchain for index: %d", outputID)
+	}
+
+	if len(fc.filters) > 0 && !slices.Contains(ff.fg, fc) {
+		return nil, fmt.Errorf("filterchain is not chained in the ffmpeg command")
+	}
+
+	// Find the filterchains which the audio depends on.
+	// Filterchains without filters are skipped by the filtergraph. Their outputs are the inputs.
+	producers := make(map[string]*FilterChain)
+	for _, c := range ff.fg {
+		if len(c.filters) == 0 {
+			continue
+		}
+		for _, output := range audioFilterChain(c).outputs {
+			producers[output] = c
+		}
+	}
+
+	if len(fc.filters) > 0 {
+		if _, ok := producers[label]; !ok {
+			return nil, fmt.Errorf("output %s of filterchain is not audio", label)
+		}
+	}
+
+	deps := make(map[*FilterChain]*FilterChain)
+	queue := []string{label}
+	for len(queue) > 0 {
+		p, ok := producers[queue[0]]
+		queue = queue[1:]
+		if !ok {
+			continue
+		}
+
+		if _, ok := deps[p]; ok {
+			continue
+		}
+
+		c := audioFilterChain(p)
+		deps[p] = c
+		queue = append(queue, c.Inputs()...)
+	}
+
+	m := New("/dev/null", true)
+	m.AddOutputOptions("-f", "null")
+
+	// Keep the inputs used by the audio and update the input IDs in the order of filtergraph.
+	ids := make(map[int]int)
+	remap := func(input string) string {
+		sm := inputStreamRegexp.FindStringSubmatch(input)
+		if sm == nil {
+			return input
+		}
+
+		id, _ := strconv.Atoi(sm[1])
+		if id < 0 || id >= len(ff.inputs) {
+			return input
+		}
+
+		newID, ok := ids[id]
+		if !ok {
+			newID = m.AddInputWithOptions(ff.inputs[id], ff.inputOptions[id]...)
+			ids[id] = newID
+		}
+		return fmt.Sprintf("[%d%s]", newID, sm[2])
+	}
+
+	consumed := make(map[string]struct{})
+	var fcs []*FilterChain
+	for _, c := range ff.fg {
+		dep, ok := deps[c]
+		if !ok {
+			continue
+		}
+
+		var inputs []any
+		for _, input := range dep.Inputs() {
+			inputs = append(inputs, remap(input))
+			consumed[input] = struct{}{}
+		}
+		dep.inputs = inputs
+		fcs = append(fcs, dep)
+	}
+
+	measure := NewFilterChain("[loudnorm_measure]")
+	measure.AddInput(remap(label))
+	measure.Chain(filter)
+	consumed[label] = struct{}{}
+
+	// Labeled outputs of the filtergraph must be mapped once.
+	// Map the outputs consumed by the filterchains not in the measurement(e.g. other outputs of asplit) to discard them.
+	for _, c := range fcs {
+		m.Chain(c)
+		for _, output := range c.outputs {
+			if _, ok := consumed[output]; !ok {
+				m.Map(output)
+			}
+		}
+	}
+	m.Chain(measure)
+
+	// Keep the pre-commands creating the inputs or files used by the filters, and the post-commands removing them.
+	used := make(map[string]struct{})
+	for _, in := range m.inputs {
+		used[filepath.Clean(in)] = struct{}{}
+	}
+	for _, c := range fcs {
+		for _, f := range c.filters {
+			files, _ := filterFiles(f)
+			for _, file := range files {
+				used[filepath.Clean(file)] = struct{}{}
+			}
+		}
+	}
+
+	created := make(map[string]struct{})
+	for _, cmd := range ff.preCmds {
+		c, ok := cmd.(interface{ createdFiles() []string })
+		if !ok {
+			continue
+		}
+
+		for _, file := range c.createdFiles() {
+			if _, ok := used[filepath.Clean(file)]; ok {
+				m.AddPreCmd(cmd)
+				created[filepath.Clean(file)] = struct{}{}
+				break
+			}
+		}
+	}
+
+	for _, cmd := range ff.postCmds {
+		c, ok := cmd.(interface{ removedFiles() []string })
+		if !ok {
+			continue
+		}
+
+		for _, file := range c.removedFiles() {
+			if _, ok := created[filepath.Clean(file)]; ok {
+				m.AddPostCmd(cmd)
+				break
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// MeasureRenderLoudness runs ffmpeg to measure the loudness(pass 1) of the audio at the point of the render and returns the measured values.
+// See LoudnessTarget.MeasureFFmpeg() for more.
+// dir: working dir to run ffmpeg.
+// ff: ffmpeg command to render the output.
+// fc, outputID: audio filterchain of the render and the 0-based index of its output to normalize.
+// target: loudness target.
+func MeasureRenderLoudness(dir string, ff *FFmpeg, fc *FilterChain, outputID int, target *LoudnessTarget) (*LoudnessMeasurement, error) {
+	m, err := target.MeasureFFmpeg(ff, fc, outputID)
+	if err != nil {
+		return nil, err
+	}
+
+	var stderr bytes.Buffer
+	fn := func(stdout, stderrPipe io.ReadCloser) error {
+		_, err := io.Copy(&stderr, stderrPipe)
+		return err
+	}
+
+	if err := m.Run(dir, fn); err != nil {
+		return nil, fmt.Errorf("m.Run() error: %v", err)
+	}
+
+	return ParseLoudnormOutput(stderr.String())
+}
@@ -0,0 +1,136 @@
+package ffcmd_test
+
+import (
+	"fmt"
+
+	"github.com/northbright/ffcmd"
+)
+
+func ExampleParseLoudnormOutput() {
+	// Output(stderr) of pass 1:
+	// ffmpeg -i input.mp4 -map 0:a:0 -af loudnorm=I=-14:TP=-1:LRA=11:print_format=json -f null -
+	// Use ffcmd.MeasureLoudness() to run pass 1 and parse the output.
+	stderr := `Input #0, mov,mp4,m4a,3gp,3g2,mj2, from 'input.mp4':
+  Duration: 00:01:05.12, start: 0.000000, bitrate: 8153 kb/s
+[Parsed_loudnorm_0 @ 0x600001e54000]
+{
+	"input_i" : "-27.61",
+	"input_tp" : "-4.47",
+	"input_lra" : "18.06",
+	"input_thresh" : "-39.20",
+	"output_i" : "-14.58",
+	"output_tp" : "-1.00",
+	"output_lra" : "10.01",
+	"output_thresh" : "-26.17",
+	"normalization_type" : "dynamic",
+	"target_offset" : "0.58"
+}`
+
+	m, err := ffcmd.ParseLoudnormOutput(stderr)
+	if err != nil {
+		fmt.Printf("ffcmd.ParseLoudnormOutput() error: %v", err)
+		return
+	}
+
+	fmt.Printf("I: %.2f, TP: %.2f, LRA: %.2f, thresh: %.2f, offset: %.2f\n", m.InputI, m.InputTP, m.InputLRA, m.InputThresh, m.TargetOffset)
+
+	// Pass 2: inject linear loudnorm into the audio filterchain of the real render.
+	profile := ffcmd.Profile{W: 1280, H: 720, FPS: 30}
+
+	ff := ffcmd.New("output.mp4", true)
+	ff.AddInput("input.mp4")
+
+	a := ffcmd.NewFilterChain("[outa]")
+	a.AddInputByID(0, "a", 0)
+
+	if err := ffcmd.LoudnessStreaming.Normalize(a, m); err != nil {
+		fmt.Printf("Normalize() error: %v", err)
+		return
+	}
+	// Resample the audio upsampled by loudnorm.
+	profile.NormalizeAudio(a)
+	ff.Chain(a)
+
+	str, err := ff.String()
+	if err != nil {
+		fmt.Printf("ff.String() error: %v", err)
+		return
+	}
+
+	fmt.Println(str)
+
+	// Output:
+	// I: -27.61, TP: -4.47, LRA: 18.06, thresh: -39.20, offset: 0.58
+	// echo "y" | ffmpeg \
+	// -i "input.mp4" \
+	// -filter_complex " \
+	// [0:a:0]loudnorm=I=-14:TP=-1:LRA=11:measured_I=-27.61:measured_TP=-4.47:measured_LRA=18.06:measured_thresh=-39.20:offset=0.58:linear=true,aresample=48000,aformat=sample_fmts=fltp:sample_rates=48000:channel_layouts=stereo[outa]" \
+	// -map "[outa]" \
+	// output.mp4
+}
+
+func ExampleLoudnessTarget_MeasureFFmpeg() {
+	// Render: two clips with subtitles and a logo are concatenated.
+	ff := ffcmd.New("output.mp4", true)
+	logo := ff.AddInput("logo.png")
+
+	var segments []*ffcmd.FilterChain
+	for i, file := range []string{"01.mp4", "02.mp4"} {
+		id := ff.AddInput(file)
+		srt := fmt.Sprintf("%02d.srt", i+1)
+
+		createCmd, _ := ffcmd.NewCreateOneSubSRTCmd(srt, file, "Hello", "", "00:00:05")
+		ff.AddPreCmd(createCmd)
+		removeCmd, _ := ffcmd.NewRemoveOneSubSRTCmd(srt)
+		ff.AddPostCmd(removeCmd)
+
+		v := ffcmd.NewFilterChain(fmt.Sprintf("[clip_%02d_v]", i))
+		v.AddInputByID(id, "v", 0)
+		v.AddInputByID(logo, "v", 0)
+		v.Chain("overlay=x=10:y=10").Chain("trim=end=5").Chain("setpts=PTS-STARTPTS").Chain("subtitles=" + srt)
+		ff.Chain(v)
+
+		a := ffcmd.NewFilterChain(fmt.Sprintf("[clip_%02d_a]", i))
+		a.AddInputByID(id, "a", 0)
+		a.Chain("atrim=end=5").Chain("asetpts=PTS-STARTPTS").Chain("afade=t=out:st=4:d=1")
+		ff.Chain(a)
+
+		segments = append(segments, v, a)
+	}
+
+	concat := ffcmd.NewFilterChain("[outv]", "[outa]")
+	for _, seg := range segments {
+		concat.AddInputByOutput(seg, 0)
+	}
+	concat.Chain("concat=n=2:v=1:a=1")
+	ff.Chain(concat)
+
+	// Measure the concatenated audio where loudnorm filter will be chained.
+	// The video, logo and the commands to create / remove SRT files are not needed by the audio.
+	m, err := ffcmd.LoudnessStreaming.MeasureFFmpeg(ff, concat, 1)
+	if err != nil {
+		fmt.Printf("MeasureFFmpeg() error: %v", err)
+		return
+	}
+
+	str, err := m.String()
+	if err != nil {
+		fmt.Printf("m.String() error: %v", err)
+		return
+	}
+
+	fmt.Println(str)
+
+	// Output:
+	// echo "y" | ffmpeg \
+	// -i "01.mp4" \
+	// -i "02.mp4" \
+	// -filter_complex " \
+	// [0:a:0]atrim=end=5,asetpts=PTS-STARTPTS,afade=t=out:st=4:d=1[clip_00_a];
+	// [1:a:0]atrim=end=5,asetpts=PTS-STARTPTS,afade=t=out:st=4:d=1[clip_01_a];
+	// [clip_00_a][clip_01_a]concat=n=2:v=0:a=1[outa];
+	// [outa]loudnorm=I=-14:TP=-1:LRA=11:print_format=json[loudnorm_measure]" \
+	// -map "[loudnorm_measure]" \
+	// -f null \
+	// /dev/null
+}
@@ -0,0 +1,107 @@
+package ffcmd
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Chapter represents a named time range of the output.
+type Chapter struct {
+	// Title is the title of the chapter.
+	Title string
+	// Start, End are the start / end time of the chapter in seconds.
+	Start float32
+	End   float32
+}
+
+// Metadata represents the container metadata(tags) and chapters of the output.
+type Metadata struct {
+	// Tags are the global tags(e.g. "title", "artist", "comment").
+	Tags map[string]string
+	// Chapters are the chapters in order of start time.
+	Chapters []Chapter
+}
+
+// escapeMetadata escapes the special characters('=', ';', '#', '\' and newline) of the FFMETADATA file.
+func escapeMetadata(str string) string {
+	return escapeChars(str, "\\=;#\n")
+}
+
+// String returns the content of the FFMETADATA file.
+func (m *Metadata) String() (string, error) {
+	str := ";FFMETADATA1\n"
+
+	// Sort tags by keys to generate the same content.
+	var keys []string
+	for k := range m.Tags {
+		if k == "" {
+			return "", fmt.Errorf("empty tag key")
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		str += fmt.Sprintf("%s=%s\n", escapeMetadata(k), escapeMetadata(m.Tags[k]))
+	}
+
+	var end float32
+	for i, c := range m.Chapters {
+		if c.Start < 0 || c.End <= c.Start {
+			return "", fmt.Errorf("chapter %d: invalid time range", i)
+		}
+
+		if c.Start < end {
+			return "", fmt.Errorf("chapter %d: overlaps previous chapter", i)
+		}
+		end = c.End
+
+		str += "\n[CHAPTER]\nTIMEBASE=1/1000\n"
+		str += fmt.Sprintf("START=%d\n", int64(math.Round(float64(c.Start)*1000)))
+		str += fmt.Sprintf("END=%d\n", int64(math.Round(float64(c.End)*1000)))
+		if c.Title != "" {
+			str += fmt.Sprintf("title=%s\n", escapeMetadata(c.Title))
+		}
+	}
+
+	return str, nil
+}
+
+// SetMetadata writes the metadata to the FFMETADATA file by a pre-command, removes it by a post-command,
+// adds it as an input and maps its metadata and chapters to the output.
+// file: FFMETADATA file to create(e.g. "metadata.txt").
+func (ff *FFmpeg) SetMetadata(m *Metadata, file string) error {
+	content, err := m.String()
+	if err != nil {
+		return fmt.Errorf("m.String() error: %v", err)
+	}
+
+	createCmd, err := NewCreateFileCmd(file, content)
+	if err != nil {
+		return fmt.Errorf("NewCreateFileCmd() error: %v", err)
+	}
+
+	removeCmd, err := NewRemoveFileCmd(file)
+	if err != nil {
+		return fmt.Errorf("NewRemoveFileCmd() error: %v", err)
+	}
+
+	ff.AddPreCmd(createCmd)
+	ff.AddPostCmd(removeCmd)
+
+	id := ff.AddInput(file)
+	ff.MapMetadata(id)
+	if len(m.Chapters) > 0 {
+		ff.MapChapters(id)
+	}
+
+	return nil
+}
+
+// metadataFileName returns the FFMETADATA filename for the output(e.g. "output_metadata.txt" for "output.mp4").
+func metadataFileName(output string) string {
+	return strings.TrimSuffix(output, filepath.Ext(output)) + "_metadata.txt"
+}
@@ -0,0 +1,94 @@
+package ffcmd_test
+
+import (
+	"fmt"
+
+	"github.com/northbright/ffcmd"
+)
+
+func ExampleMetadata() {
+	m := &ffcmd.Metadata{
+		Tags: map[string]string{
+			"title":   "Trip to Hangzhou",
+			"artist":  "Frank",
+			"comment": "Day 1; West Lake = #1",
+		},
+		Chapters: []ffcmd.Chapter{
+			{Title: "Intro", Start: 0, End: 3.5},
+			{Title: "West Lake", Start: 3.5, End: 65.12},
+		},
+	}
+
+	content, err := m.String()
+	if err != nil {
+		fmt.Printf("m.String() error: %v", err)
+		return
+	}
+
+	fmt.Println(content)
+
+	ff := ffcmd.New("output.mp4", true)
+	ff.AddInput("input.mp4")
+
+	fc := ffcmd.NewFilterChain("[outv]")
+	fc.AddInputByID(0, "v", 0)
+	fc.Chain("null")
+	ff.Chain(fc)
+
+	if err := ff.SetMetadata(m, "metadata.txt"); err != nil {
+		fmt.Printf("ff.SetMetadata() error: %v", err)
+		return
+	}
+
+	str, err := ff.String()
+	if err != nil {
+		fmt.Printf("ff.String() error: %v", err)
+		return
+	}
+
+	fmt.Println(str)
+
+	// Output:
+	// ;FFMETADATA1
+	// artist=Frank
+	// comment=Day 1\; West Lake \= \#1
+	// title=Trip to Hangzhou
+	//
+	// [CHAPTER]
+	// TIMEBASE=1/1000
+	// START=0
+	// END=3500
+	// title=Intro
+	//
+	// [CHAPTER]
+	// TIMEBASE=1/1000
+	// START=3500
+	// END=65120
+	// title=West Lake
+	//
+	// printf '%s' ';FFMETADATA1
+	// artist=Frank
+	// comment=Day 1\; West Lake \= \#1
+	// title=Trip to Hangzhou
+	//
+	// [CHAPTER]
+	// TIMEBASE=1/1000
+	// START=0
+	// END=3500
+	// title=Intro
+	//
+	// [CHAPTER]
+	// TIMEBASE=1/1000
+	// START=3500
+	// END=65120
+	// title=West Lake
+	// ' > "metadata.txt" && echo "y" | ffmpeg \
+	// -i "input.mp4" \
+	// -i "metadata.txt" \
+	// -filter_complex " \
+	// [0:v:0]null[outv]" \
+	// -map "[outv]" \
+	// -map_metadata 1 \
+	// -map_chapters 1 \
+	// output.mp4 && rm "metadata.txt"
+}
@@ -0,0 +1,188 @@
+package ffcmd
+
+import (
+	"fmt"
+)
+
+// Anchor represents the position to place an overlay or text on the base video.
+type Anchor int
+
+const (
+	AnchorTopLeft Anchor = iota
+	AnchorTop
+	AnchorTopRight
+	AnchorLeft
+	AnchorCenter
+	AnchorRight
+	AnchorBottomLeft
+	AnchorBottom
+	AnchorBottomRight
+	// AnchorCustom uses custom x / y expressions.
+	AnchorCustom
+)
+
+// anchorXY returns the x / y expressions by the anchor and margin.
+// W, H: variables of base width / height in the filter's expression.
+// w, h: variables of the placed object's width / height in the filter's expression.
+// x, y: custom x / y expressions used for AnchorCustom.
+func anchorXY(anchor Anchor, margin int, W, H, w, h, x, y Expr) (Expr, Expr) {
+	m := Const(float64(margin))
+	left, top := m, m
+	right, bottom := Sub(W, w), Sub(H, h)
+
+	if margin != 0 {
+		right = Sub(right, m)
+		bottom = Sub(bottom, m)
+	}
+
+	centerX := Div(Sub(W, w), Const(2))
+	centerY := Div(Sub(H, h), Const(2))
+
+	switch anchor {
+	case AnchorTop:
+		return centerX, top
+	case AnchorTopRight:
+		return right, top
+	case AnchorLeft:
+		return left, centerY
+	case AnchorCenter:
+		return centerX, centerY
+	case AnchorRight:
+		return right, centerY
+	case AnchorBottomLeft:
+		return left, bottom
+	case AnchorBottom:
+		return centerX, bottom
+	case AnchorBottomRight:
+		return right, bottom
+	case AnchorCustom:
+		if x == nil {
+			x = Const(0)
+		}
+		if y == nil {
+			y = Const(0)
+		}
+		return x, y
+	default:
+		return left, top
+	}
+}
+
+// enableExpr returns the expression for the timeline editing("enable" option) by start and end timestamp.
+// It returns nil if both start and end are empty.
+func enableExpr(start, end string) (Expr, error) {
+	var s, e Expr
+
+	if start != "" {
+		ts, err := NewTimestamp(start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start time format")
+		}
+		s = Const(ts.seconds())
+	}
+
+	if end != "" {
+		ts, err := NewTimestamp(end)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end time format")
+		}
+		e = Const(ts.seconds())
+	}
+
+	switch {
+	case s != nil && e != nil:
+		return Between(VarT, s, e), nil
+	case s != nil:
+		return Gte(VarT, s), nil
+	case e != nil:
+		return Lte(VarT, e), nil
+	default:
+		return nil, nil
+	}
+}
+
+// Overlay represents the options to composite an image or video(e.g. logo watermark, picture-in-picture, lower-third) on the base video.
+type Overlay struct {
+	// Anchor is the position to place the overlay.
+	Anchor Anchor
+	// X, Y are the x / y expressions of overlay filter used for AnchorCustom(e.g. Mul(Var("main_w"), Const(0.1))).
+	X Expr
+	Y Expr
+	// Margin is the margin in pixels to the edges of base video.
+	Margin int
+	// Opacity is the opacity of the overlay in the range (0, 1).
+	// 0 or 1 means no change of opacity.
+	Opacity float32
+	// Scale is the width of the overlay relative to the width of base video(e.g. 0.25).
+	// The aspect ratio of the overlay is kept. 0 means no scaling.
+	Scale float32
+	// Start, End are the timestamps in the "HH:MM:SS(.mmm)" format to show the overlay.
+	// The overlay is shown all the time if both are empty.
+	Start string
+	End   string
+	// Enable is the custom expression to show the overlay(e.g. Gt(Call("mod", VarT, Const(10)), Const(5))).
+	// It overrides Start and End.
+	Enable Expr
+}
+
+// FilterChains returns the filterchains to composite the overlay on the base video.
+// base, baseID: filterchain of base video and the 0-based index of its output.
+// over, overID: filterchain of overlay and the 0-based index of its output.
+// output: output label of the composited video in the "[OUTPUT_LABEL]" format.
+// The last filterchain outputs the composited video.
+func (o *Overlay) FilterChains(base *FilterChain, baseID int, over *FilterChain, overID int, output string) ([]*FilterChain, error) {
+	if o.Opacity < 0 || o.Opacity > 1 {
+		return nil, fmt.Errorf("invalid opacity")
+	}
+
+	if o.Scale < 0 {
+		return nil, fmt.Errorf("invalid scale")
+	}
+
+	enable := o.Enable
+	if enable == nil {
+		var err error
+		if enable, err = enableExpr(o.Start, o.End); err != nil {
+			return nil, fmt.Errorf("enableExpr() error: %v", err)
+		}
+	}
+
+	var fcs []*FilterChain
+	name := labelName(output)
+
+	// Set opacity of overlay.
+	if o.Opacity > 0 && o.Opacity < 1 {
+		fc := NewFilterChain(fmt.Sprintf("[%s_ov]", name))
+		fc.AddInputByOutput(over, overID)
+		fc.Chain("format=rgba").Chain(fmt.Sprintf("colorchannelmixer=aa=%.2f", o.Opacity))
+		fcs = append(fcs, fc)
+
+		over, overID = fc, 0
+	}
+
+	// Scale overlay relative to the base video.
+	if o.Scale > 0 {
+		fc := NewFilterChain(fmt.Sprintf("[%s_ov_scaled]", name), fmt.Sprintf("[%s_base]", name))
+		fc.AddInputByOutput(over, overID)
+		fc.AddInputByOutput(base, baseID)
+		fc.Chain(fmt.Sprintf("scale2ref=w=main_w*%.3f:h=ow/dar", o.Scale))
+		fcs = append(fcs, fc)
+
+		over, overID = fc, 0
+		base, baseID = fc, 1
+	}
+
+	x, y := anchorXY(o.Anchor, o.Margin, Var("main_w"), Var("main_h"), Var("overlay_w"), Var("overlay_h"), o.X, o.Y)
+	overlay := fmt.Sprintf("overlay=x=%s:y=%s", FormatExpr(x), FormatExpr(y))
+	if enable != nil {
+		overlay += fmt.Sprintf(":enable=%s", FormatExpr(enable))
+	}
+
+	fc := NewFilterChain(output)
+	fc.AddInputByOutput(base, baseID)
+	fc.AddInputByOutput(over, overID)
+	fc.Chain(overlay)
+	fcs = append(fcs, fc)
+
+	return fcs, nil
+}
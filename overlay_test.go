@@ -0,0 +1,62 @@
+package ffcmd_test
+
+import (
+	"fmt"
+
+	"github.com/northbright/ffcmd"
+)
+
+func ExampleOverlay() {
+	ffmpeg := ffcmd.New("output.mp4", true)
+
+	// Base video.
+	base := ffcmd.NewFilterChain()
+	base.AddInputByID(ffmpeg.AddInput("input.mp4"), "v", 0)
+
+	// Logo image.
+	logo := ffcmd.NewFilterChain()
+	logo.AddInputByID(ffmpeg.AddInput("logo.png"), "v", 0)
+
+	// Place a half-transparent logo at the bottom right corner from 1s to 10s.
+	// The width of logo is 20% of base video.
+	o := &ffcmd.Overlay{
+		Anchor:  ffcmd.AnchorBottomRight,
+		Margin:  20,
+		Opacity: 0.5,
+		Scale:   0.2,
+		Start:   "00:00:01",
+		End:     "00:00:10",
+	}
+
+	fcs, err := o.FilterChains(base, 0, logo, 0, "[outv]")
+	if err != nil {
+		fmt.Printf("o.FilterChains() error: %v", err)
+		return
+	}
+
+	for _, fc := range fcs {
+		ffmpeg.Chain(fc)
+	}
+
+	ffmpeg.MapByID(0, "a", 0)
+
+	str, err := ffmpeg.String()
+	if err != nil {
+		fmt.Printf("ffmpeg.String() error: %v", err)
+		return
+	}
+
+	fmt.Println(str)
+
+	// Output:
+	// echo "y" | ffmpeg \
+	// -i "input.mp4" \
+	// -i "logo.png" \
+	// -filter_complex " \
+	// [1:v:0]format=rgba,colorchannelmixer=aa=0.50[outv_ov];
+	// [outv_ov][0:v:0]scale2ref=w=main_w*0.200:h=ow/dar[outv_ov_scaled][outv_base];
+	// [outv_base][outv_ov_scaled]overlay=x=main_w-overlay_w-20:y=main_h-overlay_h-20:enable='between(t,1,10)'[outv]" \
+	// -map "0:a:0" \
+	// -map "[outv]" \
+	// output.mp4
+}
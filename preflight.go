@@ -0,0 +1,312 @@
+package ffcmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// filterOption represents an option of filter. Key is empty for the option set by position(e.g. "op.srt" of "subtitles=op.srt").
+type filterOption struct {
+	key   string
+	value string
+}
+
+// getToken returns the unescaped token of str until one of the delimiters and the rest of str.
+// It removes the escaping of backslash and quoting of single quotes as ffmpeg's av_get_token().
+func getToken(str, delims string) (string, string) {
+	var b strings.Builder
+
+	str = strings.TrimLeft(str, " \n\t\r")
+	// Length of the token without trailing whitespaces which are not escaped or quoted.
+	end := 0
+
+	i := 0
+	for ; i < len(str) && !strings.ContainsRune(delims, rune(str[i])); i++ {
+		switch c := str[i]; c {
+		case '\\':
+			if i+1 < len(str) {
+				i++
+				b.WriteByte(str[i])
+			}
+			end = b.Len()
+		case '\'':
+			for i++; i < len(str) && str[i] != '\''; i++ {
+				b.WriteByte(str[i])
+			}
+			end = b.Len()
+		default:
+			b.WriteByte(c)
+			if !strings.ContainsRune(" \n\t\r", rune(c)) {
+				end = b.Len()
+			}
+		}
+	}
+
+	return b.String()[:end], str[i:]
+}
+
+// optionKey matches the key of filter option.
+var optionKey = regexp.MustCompile(`^[A-Za-z0-9_\-/.]+=`)
+
+// parseFilter returns the name and options of the filter(e.g. "drawtext=fontfile=font.ttf:text=Hello").
+// It removes the escaping of filtergraph and filter options.
+func parseFilter(filter string) (string, []filterOption) {
+	// Remove the escaping of filtergraph.
+	desc, _ := getToken(filter, "[],;")
+
+	name, args, found := strings.Cut(desc, "=")
+	if !found {
+		return strings.TrimSpace(name), nil
+	}
+
+	var opts []filterOption
+	for {
+		var opt filterOption
+
+		if key := optionKey.FindString(args); key != "" {
+			opt.key = strings.TrimSuffix(key, "=")
+			args = args[len(key):]
+		}
+
+		// Remove the escaping of filter options.
+		opt.value, args = getToken(args, ":")
+		opts = append(opts, opt)
+
+		if args == "" {
+			break
+		}
+		// Skip ':'.
+		args = args[1:]
+	}
+
+	return strings.TrimSpace(name), opts
+}
+
+// filterFileOptions are the options of filters which reference files.
+// The first one is also the option set by position if the filter supports it.
+var filterFileOptions = map[string][]string{
+	"subtitles": {"filename", "f"},
+	"ass":       {"filename", "f"},
+	"movie":     {"filename"},
+	"amovie":    {"filename"},
+	"lut3d":     {"file"},
+	"drawtext":  {"fontfile", "textfile"},
+}
+
+// filterDirOptions are the options of filters which reference dirs.
+var filterDirOptions = map[string][]string{
+	"subtitles": {"fontsdir"},
+	"ass":       {"fontsdir"},
+}
+
+// filterFiles returns the files and dirs referenced by the filter(e.g. SRT file and fonts dir of subtitles filter, font file of drawtext filter).
+func filterFiles(filter string) (files []string, dirs []string) {
+	name, opts := parseFilter(filter)
+
+	fileKeys, hasFiles := filterFileOptions[name]
+	dirKeys, hasDirs := filterDirOptions[name]
+	if !hasFiles && !hasDirs {
+		return nil, nil
+	}
+
+	for i, opt := range opts {
+		if opt.value == "" {
+			continue
+		}
+
+		if opt.key == "" {
+			if i == 0 && hasFiles && name != "drawtext" {
+				files = append(files, opt.value)
+			}
+			continue
+		}
+
+		if slices.Contains(fileKeys, opt.key) {
+			files = append(files, opt.value)
+		}
+		if slices.Contains(dirKeys, opt.key) {
+			dirs = append(dirs, opt.value)
+		}
+	}
+
+	return files, dirs
+}
+
+// createdFiles returns the file created by the command.
+func (cmd *CreateFileCmd) createdFiles() []string {
+	return []string{cmd.file}
+}
+
+// createdFiles returns the SRT file created by the command.
+func (cmd *CreateOneSubSRTCmd) createdFiles() []string {
+	return []string{cmd.srtFile}
+}
+
+// createdFiles returns the output of ffmpeg(e.g. palette file of GIF generated by a pre-command).
+func (ff *FFmpeg) createdFiles() []string {
+	return []string{ff.output}
+}
+
+// isFile returns if the input / output is a local file instead of URL, pipe or device.
+func isFile(file string) bool {
+	return file != "" && file != "-" && file != "/dev/null" && !strings.Contains(file, "://") && !strings.HasPrefix(file, "pipe:")
+}
+
+// checkReadable checks if the file exists and is readable.
+func checkReadable(file string) error {
+	fi, err := os.Stat(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("not found")
+		}
+		return err
+	}
+
+	if fi.IsDir() {
+		return fmt.Errorf("is a directory")
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("unreadable: %v", err)
+	}
+	f.Close()
+
+	return nil
+}
+
+// checkDir checks if the dir exists.
+func checkDir(dir string) error {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("not found")
+		}
+		return err
+	}
+
+	if !fi.IsDir() {
+		return fmt.Errorf("not a directory")
+	}
+
+	return nil
+}
+
+// checkWritableDir checks if the dir exists and files can be created in it.
+func checkWritableDir(dir string) error {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("output dir not found")
+		}
+		return err
+	}
+
+	if !fi.IsDir() {
+		return fmt.Errorf("output dir is not a directory")
+	}
+
+	f, err := os.CreateTemp(dir, ".ffcmd_preflight_*")
+	if err != nil {
+		return fmt.Errorf("output dir is not writable: %v", err)
+	}
+	f.Close()
+	os.Remove(f.Name())
+
+	return nil
+}
+
+// Preflight checks the problems before running the ffmpeg command and reports all of them at once by errors.Join().
+// It checks if the inputs and files referenced by filters(SRT, fonts, etc.) exist and are readable,
+// if the dirs referenced by filters(e.g. fontsdir of subtitles filter) exist,
+// if the output dir is writable and if the output exists when overwrite is false.
+// The files created by pre-commands(e.g. SRT files, palette files) are skipped.
+// dir: working dir to find the files. It should be the same dir passed to Run().
+func (ff *FFmpeg) Preflight(dir string) error {
+	var errs []error
+
+	// Files created by pre-commands do not exist before running.
+	created := make(map[string]struct{})
+	for _, cmd := range ff.preCmds {
+		if c, ok := cmd.(interface{ createdFiles() []string }); ok {
+			for _, file := range c.createdFiles() {
+				created[filepath.Clean(file)] = struct{}{}
+			}
+		}
+
+		// Check the ffmpeg commands as pre-commands.
+		if pre, ok := cmd.(*FFmpeg); ok {
+			if err := pre.Preflight(dir); err != nil {
+				errs = append(errs, fmt.Errorf("pre-command: %w", err))
+			}
+		}
+	}
+
+	isCreated := func(file string) bool {
+		_, ok := created[filepath.Clean(file)]
+		return ok
+	}
+
+	for _, in := range ff.inputs {
+		if !isFile(in) || isCreated(in) {
+			continue
+		}
+
+		if err := checkReadable(resolvePath(dir, in)); err != nil {
+			errs = append(errs, fmt.Errorf("input %q: %v", in, err))
+		}
+	}
+
+	// Files referenced by filters are relative to the working dir.
+	checked := make(map[string]struct{})
+	for _, fc := range ff.fg {
+		for _, filter := range fc.filters {
+			files, dirs := filterFiles(filter)
+			for _, file := range files {
+				if _, ok := checked[file]; ok || isCreated(file) {
+					continue
+				}
+				checked[file] = struct{}{}
+
+				if err := checkReadable(resolvePath(dir, file)); err != nil {
+					errs = append(errs, fmt.Errorf("file %q referenced by filter: %v", file, err))
+				}
+			}
+
+			for _, d := range dirs {
+				if _, ok := checked[d]; ok {
+					continue
+				}
+				checked[d] = struct{}{}
+
+				if err := checkDir(resolvePath(dir, d)); err != nil {
+					errs = append(errs, fmt.Errorf("dir %q referenced by filter: %v", d, err))
+				}
+			}
+		}
+	}
+
+	if isFile(ff.output) {
+		output := resolvePath(dir, ff.output)
+
+		if err := checkWritableDir(filepath.Dir(output)); err != nil {
+			errs = append(errs, fmt.Errorf("output %q: %v", ff.output, err))
+		}
+
+		if fi, err := os.Stat(output); err == nil {
+			switch {
+			case fi.IsDir():
+				errs = append(errs, fmt.Errorf("output %q: is a directory", ff.output))
+			case !ff.overwrite && !isCreated(ff.output):
+				errs = append(errs, fmt.Errorf("output %q: exists and overwrite is false", ff.output))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,70 @@
+package ffcmd_test
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/northbright/ffcmd"
+)
+
+func ExampleFFmpeg_Preflight() {
+	dir, err := os.MkdirTemp("", "ffcmd")
+	if err != nil {
+		log.Printf("os.MkdirTemp() error: %v", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	// "01.MOV", "02.srt" and "output.mp4" exist. "02.MOV", "font.ttf", "logo.png" and "fonts" dir are missing.
+	for _, file := range []string{"01.MOV", "02.srt", "output.mp4"} {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte("dummy"), 0644); err != nil {
+			log.Printf("os.WriteFile() error: %v", err)
+			return
+		}
+	}
+
+	ff := ffcmd.New("output.mp4", false)
+	ff.AddInput("01.MOV")
+	ff.AddInput("02.MOV")
+
+	// "01.srt" is created by the pre-command.
+	createCmd, _ := ffcmd.NewCreateOneSubSRTCmd("01.srt", "01.MOV", "Hello", "", "00:00:05")
+	ff.AddPreCmd(createCmd)
+
+	dt := &ffcmd.DrawText{Text: "ffcmd", FontFile: "font.ttf", FontSize: 24}
+	drawtext, _ := dt.Filter()
+
+	// Files referenced by absolute paths are not relative to the working dir.
+	srt := filepath.Join(dir, "02.srt")
+	subtitles := fmt.Sprintf("subtitles=%s:fontsdir=fonts", ffcmd.EscapeFilterOptionValue(srt))
+
+	logo := ffcmd.NewFilterChain("[logo]")
+	logo.Chain("movie=logo.png")
+	ff.Chain(logo)
+
+	v := ffcmd.NewFilterChain("[main]")
+	v.AddInputByID(0, "v", 0)
+	v.AddInputByID(1, "v", 0)
+	v.Chain("concat=n=2:v=1:a=0").Chain("subtitles=01.srt").Chain(subtitles).Chain(drawtext)
+	ff.Chain(v)
+
+	out := ffcmd.NewFilterChain("[outv]")
+	out.AddInputByOutput(v, 0)
+	out.AddInputByOutput(logo, 0)
+	out.Chain("overlay")
+	ff.Chain(out)
+
+	if err := ff.Preflight(dir); err != nil {
+		fmt.Printf("ff.Preflight() error:\n%v\n", err)
+	}
+
+	// Output:
+	// ff.Preflight() error:
+	// input "02.MOV": not found
+	// file "logo.png" referenced by filter: not found
+	// dir "fonts" referenced by filter: not found
+	// file "font.ttf" referenced by filter: not found
+	// output "output.mp4": exists and overwrite is false
+}
@@ -0,0 +1,364 @@
+package ffcmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// resolvePath returns the path of file relative to dir.
+// It returns file directly if it's an absolute path.
+func resolvePath(dir, file string) string {
+	if dir == "" || filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(dir, file)
+}
+
+// runFFprobe runs ffprobe with arguments and returns the output of stdout.
+func runFFprobe(ctx context.Context, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "ffprobe", args...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffprobe error: %v, stderr: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// runFFmpeg runs ffmpeg with arguments in the working dir and returns the output of stderr.
+// It's used to run the analysis filters(e.g. loudnorm, silencedetect) which print the results to stderr.
+func runFFmpeg(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", append([]string{"-hide_banner", "-nostats"}, args...)...)
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg error: %v, stderr: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stderr.Bytes(), nil
+}
+
+// Rational represents a rational number(e.g. frame rate "30000/1001", aspect ratio "16:9").
+type Rational struct {
+	Num int
+	Den int
+}
+
+// ParseRational parses the rational number in the "NUM/DEN" or "NUM:DEN" format.
+func ParseRational(str string) (Rational, error) {
+	sep := "/"
+	if strings.Contains(str, ":") {
+		sep = ":"
+	}
+
+	arr := strings.Split(str, sep)
+	if len(arr) != 2 {
+		return Rational{}, fmt.Errorf("incorrect rational: %q", str)
+	}
+
+	num, err := strconv.Atoi(strings.TrimSpace(arr[0]))
+	if err != nil {
+		return Rational{}, fmt.Errorf("incorrect numerator: %q", str)
+	}
+
+	den, err := strconv.Atoi(strings.TrimSpace(arr[1]))
+	if err != nil {
+		return Rational{}, fmt.Errorf("incorrect denominator: %q", str)
+	}
+
+	return Rational{Num: num, Den: den}, nil
+}
+
+// Float64 returns the value of rational number. It returns 0 if the denominator is 0(e.g. "0/0" for unknown frame rate).
+func (r Rational) Float64() float64 {
+	if r.Den == 0 {
+		return 0
+	}
+	return float64(r.Num) / float64(r.Den)
+}
+
+// String returns the rational number in the "NUM/DEN" format.
+func (r Rational) String() string {
+	return fmt.Sprintf("%d/%d", r.Num, r.Den)
+}
+
+// Format represents the container format of media file.
+type Format struct {
+	// Filename is the path of file.
+	Filename string
+	// FormatName is the short name(s) of format(e.g. "mov,mp4,m4a,3gp,3g2,mj2").
+	FormatName string
+	// FormatLongName is the long name of format.
+	FormatLongName string
+	// Duration is the duration in seconds. 0 means unknown.
+	Duration float64
+	// Size is the file size in bytes.
+	Size int64
+	// BitRate is the total bit rate in bits/s.
+	BitRate int64
+	// NbStreams is the number of streams.
+	NbStreams int
+	// Tags are the container metadata(e.g. "title", "creation_time").
+	Tags map[string]string
+}
+
+// Stream represents a stream of media file.
+type Stream struct {
+	// Index is the index of stream in the file.
+	Index int
+	// CodecType is the type of stream("video", "audio", "subtitle", "data" or "attachment").
+	CodecType string
+	// CodecName is the short name of codec(e.g. "h264", "aac").
+	CodecName string
+	// CodecLongName is the long name of codec.
+	CodecLongName string
+	// Profile is the profile of codec(e.g. "High", "LC").
+	Profile string
+	// Width, Height are the coded size of video.
+	Width  int
+	Height int
+	// SAR is the sample aspect ratio of video.
+	SAR Rational
+	// DAR is the display aspect ratio of video.
+	DAR Rational
+	// FrameRate is the real base frame rate(r_frame_rate) of video.
+	FrameRate Rational
+	// AvgFrameRate is the average frame rate of video.
+	AvgFrameRate Rational
+	// PixFmt is the pixel format of video(e.g. "yuv420p").
+	PixFmt string
+	// Rotation is the rotation of video in degrees from display matrix or "rotate" tag(e.g. -90 for portrait phone videos).
+	Rotation int
+	// SampleRate is the sample rate of audio.
+	SampleRate int
+	// Channels is the number of audio channels.
+	Channels int
+	// ChannelLayout is the channel layout of audio(e.g. "stereo").
+	ChannelLayout string
+	// Duration is the duration of stream in seconds. 0 means unknown.
+	Duration float64
+	// BitRate is the bit rate of stream in bits/s.
+	BitRate int64
+	// Language is the language tag(e.g. "eng", "chi"). Empty means unknown.
+	Language string
+	// Title is the title tag.
+	Title string
+	// Tags are the stream metadata.
+	Tags map[string]string
+	// Disposition is the disposition flags(e.g. "default", "forced", "attached_pic") which are set.
+	Disposition map[string]bool
+}
+
+// IsVideo returns if it's a video stream. Attached pictures(e.g. cover art) are not video streams.
+func (s *Stream) IsVideo() bool {
+	return s.CodecType == "video" && !s.Disposition["attached_pic"]
+}
+
+// IsAudio returns if it's an audio stream.
+func (s *Stream) IsAudio() bool {
+	return s.CodecType == "audio"
+}
+
+// MediaInfo represents the media info of file.
+type MediaInfo struct {
+	Format  Format
+	Streams []Stream
+}
+
+// VideoStreams returns the video streams.
+func (mi *MediaInfo) VideoStreams() []Stream {
+	var streams []Stream
+	for _, s := range mi.Streams {
+		if s.IsVideo() {
+			streams = append(streams, s)
+		}
+	}
+	return streams
+}
+
+// AudioStreams returns the audio streams.
+func (mi *MediaInfo) AudioStreams() []Stream {
+	var streams []Stream
+	for _, s := range mi.Streams {
+		if s.IsAudio() {
+			streams = append(streams, s)
+		}
+	}
+	return streams
+}
+
+// HasAudio returns if the file has audio streams.
+func (mi *MediaInfo) HasAudio() bool {
+	return len(mi.AudioStreams()) > 0
+}
+
+// HasVideo returns if the file has video streams.
+func (mi *MediaInfo) HasVideo() bool {
+	return len(mi.VideoStreams()) > 0
+}
+
+// clone returns a deep copy of the media info. Caches store and return copies so that callers do not share maps or slices.
+func (mi *MediaInfo) clone() *MediaInfo {
+	c := *mi
+	c.Format.Tags = maps.Clone(mi.Format.Tags)
+	c.Streams = slices.Clone(mi.Streams)
+
+	for i := range c.Streams {
+		c.Streams[i].Tags = maps.Clone(mi.Streams[i].Tags)
+		c.Streams[i].Disposition = maps.Clone(mi.Streams[i].Disposition)
+	}
+
+	return &c
+}
+
+// probeOutput is the JSON output of ffprobe.
+type probeOutput struct {
+	Format struct {
+		Filename       string            `json:"filename"`
+		FormatName     string            `json:"format_name"`
+		FormatLongName string            `json:"format_long_name"`
+		Duration       string            `json:"duration"`
+		Size           string            `json:"size"`
+		BitRate        string            `json:"bit_rate"`
+		NbStreams      int               `json:"nb_streams"`
+		Tags           map[string]string `json:"tags"`
+	} `json:"format"`
+	Streams []struct {
+		Index              int               `json:"index"`
+		CodecType          string            `json:"codec_type"`
+		CodecName          string            `json:"codec_name"`
+		CodecLongName      string            `json:"codec_long_name"`
+		Profile            string            `json:"profile"`
+		Width              int               `json:"width"`
+		Height             int               `json:"height"`
+		SampleAspectRatio  string            `json:"sample_aspect_ratio"`
+		DisplayAspectRatio string            `json:"display_aspect_ratio"`
+		RFrameRate         string            `json:"r_frame_rate"`
+		AvgFrameRate       string            `json:"avg_frame_rate"`
+		PixFmt             string            `json:"pix_fmt"`
+		SampleRate         string            `json:"sample_rate"`
+		Channels           int               `json:"channels"`
+		ChannelLayout      string            `json:"channel_layout"`
+		Duration           string            `json:"duration"`
+		BitRate            string            `json:"bit_rate"`
+		Tags               map[string]string `json:"tags"`
+		Disposition        map[string]int    `json:"disposition"`
+		SideDataList       []struct {
+			SideDataType string  `json:"side_data_type"`
+			Rotation     float64 `json:"rotation"`
+		} `json:"side_data_list"`
+	} `json:"streams"`
+}
+
+// parseOptionalRational parses the rational number and returns zero value if it's empty or incorrect.
+func parseOptionalRational(str string) Rational {
+	r, _ := ParseRational(str)
+	return r
+}
+
+// ParseProbeOutput parses the JSON output of "ffprobe -print_format json -show_format -show_streams" and returns the media info.
+func ParseProbeOutput(data []byte) (*MediaInfo, error) {
+	var out probeOutput
+
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal() error: %v", err)
+	}
+
+	// Numbers of ffprobe are output as strings and may be missing(e.g. duration of live streams).
+	f := out.Format
+	duration, _ := strconv.ParseFloat(f.Duration, 64)
+	size, _ := strconv.ParseInt(f.Size, 10, 64)
+	bitRate, _ := strconv.ParseInt(f.BitRate, 10, 64)
+
+	mi := &MediaInfo{
+		Format: Format{
+			Filename:       f.Filename,
+			FormatName:     f.FormatName,
+			FormatLongName: f.FormatLongName,
+			Duration:       duration,
+			Size:           size,
+			BitRate:        bitRate,
+			NbStreams:      f.NbStreams,
+			Tags:           f.Tags,
+		},
+	}
+
+	for _, s := range out.Streams {
+		sampleRate, _ := strconv.Atoi(s.SampleRate)
+		duration, _ := strconv.ParseFloat(s.Duration, 64)
+		bitRate, _ := strconv.ParseInt(s.BitRate, 10, 64)
+
+		stream := Stream{
+			Index:         s.Index,
+			CodecType:     s.CodecType,
+			CodecName:     s.CodecName,
+			CodecLongName: s.CodecLongName,
+			Profile:       s.Profile,
+			Width:         s.Width,
+			Height:        s.Height,
+			SAR:           parseOptionalRational(s.SampleAspectRatio),
+			DAR:           parseOptionalRational(s.DisplayAspectRatio),
+			FrameRate:     parseOptionalRational(s.RFrameRate),
+			AvgFrameRate:  parseOptionalRational(s.AvgFrameRate),
+			PixFmt:        s.PixFmt,
+			SampleRate:    sampleRate,
+			Channels:      s.Channels,
+			ChannelLayout: s.ChannelLayout,
+			Duration:      duration,
+			BitRate:       bitRate,
+			Language:      s.Tags["language"],
+			Title:         s.Tags["title"],
+			Tags:          s.Tags,
+			Disposition:   make(map[string]bool),
+		}
+
+		for k, v := range s.Disposition {
+			if v != 0 {
+				stream.Disposition[k] = true
+			}
+		}
+
+		// Newer ffprobe reports rotation in display matrix while older one reports "rotate" tag.
+		for _, sd := range s.SideDataList {
+			if sd.SideDataType == "Display Matrix" {
+				stream.Rotation = int(math.Round(sd.Rotation))
+			}
+		}
+
+		if stream.Rotation == 0 {
+			if rotate, err := strconv.Atoi(s.Tags["rotate"]); err == nil {
+				stream.Rotation = rotate
+			}
+		}
+
+		mi.Streams = append(mi.Streams, stream)
+	}
+
+	return mi, nil
+}
+
+// Probe runs ffprobe and returns the media info of the file.
+// ctx: context to cancel ffprobe.
+// file: media file.
+func Probe(ctx context.Context, file string) (*MediaInfo, error) {
+	out, err := runFFprobe(ctx, "-v", "error", "-print_format", "json", "-show_format", "-show_streams", file)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseProbeOutput(out)
+}
@@ -0,0 +1,107 @@
+package ffcmd_test
+
+import (
+	"fmt"
+
+	"github.com/northbright/ffcmd"
+)
+
+func ExampleParseProbeOutput() {
+	// Output of "ffprobe -v error -print_format json -show_format -show_streams IMG_0001.MOV".
+	// Use ffcmd.Probe() to run ffprobe and parse the output.
+	data := []byte(`{
+    "streams": [
+        {
+            "index": 0,
+            "codec_name": "hevc",
+            "codec_long_name": "H.265 / HEVC (High Efficiency Video Coding)",
+            "profile": "Main",
+            "codec_type": "video",
+            "width": 1920,
+            "height": 1080,
+            "sample_aspect_ratio": "1:1",
+            "display_aspect_ratio": "16:9",
+            "pix_fmt": "yuv420p",
+            "r_frame_rate": "30000/1001",
+            "avg_frame_rate": "30000/1001",
+            "duration": "12.312300",
+            "bit_rate": "7841229",
+            "disposition": {
+                "default": 1,
+                "forced": 0,
+                "attached_pic": 0
+            },
+            "tags": {
+                "language": "und",
+                "handler_name": "Core Media Video"
+            },
+            "side_data_list": [
+                {
+                    "side_data_type": "Display Matrix",
+                    "displaymatrix": "\n00000000:            0       65536           0\n00000001:       -65536           0           0\n00000002:            0           0  1073741824\n",
+                    "rotation": -90
+                }
+            ]
+        },
+        {
+            "index": 1,
+            "codec_name": "aac",
+            "codec_long_name": "AAC (Advanced Audio Coding)",
+            "profile": "LC",
+            "codec_type": "audio",
+            "sample_rate": "44100",
+            "channels": 2,
+            "channel_layout": "stereo",
+            "r_frame_rate": "0/0",
+            "avg_frame_rate": "0/0",
+            "duration": "12.330000",
+            "bit_rate": "173375",
+            "disposition": {
+                "default": 1,
+                "forced": 0,
+                "attached_pic": 0
+            },
+            "tags": {
+                "language": "eng",
+                "title": "Main"
+            }
+        }
+    ],
+    "format": {
+        "filename": "IMG_0001.MOV",
+        "nb_streams": 2,
+        "format_name": "mov,mp4,m4a,3gp,3g2,mj2",
+        "format_long_name": "QuickTime / MOV",
+        "duration": "12.330000",
+        "size": "12345678",
+        "bit_rate": "8010237",
+        "tags": {
+            "creation_time": "2024-05-01T08:00:00.000000Z"
+        }
+    }
+}`)
+
+	mi, err := ffcmd.ParseProbeOutput(data)
+	if err != nil {
+		fmt.Printf("ffcmd.ParseProbeOutput() error: %v", err)
+		return
+	}
+
+	f := mi.Format
+	fmt.Printf("format: %s, duration: %.3f, size: %d, bit rate: %d\n", f.FormatName, f.Duration, f.Size, f.BitRate)
+
+	for _, s := range mi.VideoStreams() {
+		fmt.Printf("video #%d: %s(%s), %dx%d, SAR: %s, DAR: %s, frame rate: %s(%.2f), %s, rotation: %d, default: %v\n",
+			s.Index, s.CodecName, s.Profile, s.Width, s.Height, s.SAR, s.DAR, s.FrameRate, s.FrameRate.Float64(), s.PixFmt, s.Rotation, s.Disposition["default"])
+	}
+
+	for _, s := range mi.AudioStreams() {
+		fmt.Printf("audio #%d: %s, %d Hz, %d channels(%s), language: %s, title: %s\n",
+			s.Index, s.CodecName, s.SampleRate, s.Channels, s.ChannelLayout, s.Language, s.Title)
+	}
+
+	// Output:
+	// format: mov,mp4,m4a,3gp,3g2,mj2, duration: 12.330, size: 12345678, bit rate: 8010237
+	// video #0: hevc(Main), 1920x1080, SAR: 1/1, DAR: 16/9, frame rate: 30000/1001(29.97), yuv420p, rotation: -90, default: true
+	// audio #1: aac, 44100 Hz, 2 channels(stereo), language: eng, title: Main
+}
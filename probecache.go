@@ -0,0 +1,239 @@
+package ffcmd
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ProbeCache is the interface to store the media info probed by ffprobe.
+// Keys are generated by Prober with the identity of file(path, size, modification time and optional content hash).
+type ProbeCache interface {
+	// Get returns the media info of the key and if it's found.
+	Get(key string) (*MediaInfo, bool)
+	// Set stores the media info of the key.
+	Set(key string, mi *MediaInfo) error
+}
+
+// MemoryProbeCache is an in-memory probe cache with LRU eviction. It's safe for concurrent use.
+// It stores and returns copies of media infos.
+type MemoryProbeCache struct {
+	mu    sync.Mutex
+	size  int
+	lru   *list.List
+	items map[string]*list.Element
+}
+
+// memoryProbeCacheEntry is the entry of MemoryProbeCache.
+type memoryProbeCacheEntry struct {
+	key string
+	mi  *MediaInfo
+}
+
+// DefaultMemoryProbeCacheSize is the max number of media infos in the cache of DefaultProber.
+const DefaultMemoryProbeCacheSize = 256
+
+// NewMemoryProbeCache returns a new in-memory probe cache.
+// size: max number of media infos. The least recently used one is evicted when the cache is full. 0 means no limit.
+func NewMemoryProbeCache(size int) *MemoryProbeCache {
+	return &MemoryProbeCache{size: size, lru: list.New(), items: make(map[string]*list.Element)}
+}
+
+// Get returns a copy of the media info of the key and if it's found.
+func (c *MemoryProbeCache) Get(key string) (*MediaInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.lru.MoveToFront(e)
+	return e.Value.(*memoryProbeCacheEntry).mi.clone(), true
+}
+
+// Set stores a copy of the media info of the key. It evicts the least recently used one if the cache is full.
+func (c *MemoryProbeCache) Set(key string, mi *MediaInfo) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		e.Value.(*memoryProbeCacheEntry).mi = mi.clone()
+		c.lru.MoveToFront(e)
+		return nil
+	}
+
+	c.items[key] = c.lru.PushFront(&memoryProbeCacheEntry{key: key, mi: mi.clone()})
+
+	if c.size > 0 && c.lru.Len() > c.size {
+		e := c.lru.Back()
+		c.lru.Remove(e)
+		delete(c.items, e.Value.(*memoryProbeCacheEntry).key)
+	}
+
+	return nil
+}
+
+// Len returns the number of media infos in the cache.
+func (c *MemoryProbeCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+// FileProbeCache is a probe cache stored in a JSON file on disk. It's safe for concurrent use.
+// The whole file is loaded when it's created and rewritten each time a media info is set.
+type FileProbeCache struct {
+	mu    sync.RWMutex
+	file  string
+	infos map[string]*MediaInfo
+}
+
+// NewFileProbeCache returns a probe cache stored in the JSON file.
+// file: JSON file to store the cache. It'll be created if it does not exist.
+func NewFileProbeCache(file string) (*FileProbeCache, error) {
+	if file == "" {
+		return nil, fmt.Errorf("empty cache file name")
+	}
+
+	c := &FileProbeCache{file: file, infos: make(map[string]*MediaInfo)}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c, nil
+		}
+		return nil, fmt.Errorf("os.ReadFile() error: %v", err)
+	}
+
+	if err := json.Unmarshal(data, &c.infos); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal() error: %v", err)
+	}
+
+	return c, nil
+}
+
+// Get returns a copy of the media info of the key and if it's found.
+func (c *FileProbeCache) Get(key string) (*MediaInfo, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	mi, ok := c.infos[key]
+	if !ok {
+		return nil, false
+	}
+	return mi.clone(), true
+}
+
+// Set stores a copy of the media info of the key and saves the cache to the file.
+func (c *FileProbeCache) Set(key string, mi *MediaInfo) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.infos[key] = mi.clone()
+
+	data, err := json.MarshalIndent(c.infos, "", "  ")
+	if err != nil {
+		return fmt.Errorf("json.MarshalIndent() error: %v", err)
+	}
+
+	// Write to a temp file and rename it to avoid corrupting the cache file.
+	tmp := c.file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("os.WriteFile() error: %v", err)
+	}
+
+	if err := os.Rename(tmp, c.file); err != nil {
+		return fmt.Errorf("os.Rename() error: %v", err)
+	}
+
+	return nil
+}
+
+// Prober probes media files by ffprobe and caches the media info.
+type Prober struct {
+	cache ProbeCache
+	hash  bool
+}
+
+// NewProber returns a new prober.
+// cache: probe cache to store media info. nil means no cache.
+// hash: if add SHA-256 hash of file content to the key.
+// It detects the files modified without changing the size and modification time but reads the whole file for each probing.
+func NewProber(cache ProbeCache, hash bool) *Prober {
+	return &Prober{cache: cache, hash: hash}
+}
+
+// DefaultProber is the prober with an in-memory cache shared by the builders(e.g. Timeline, CreateOneSubSRTCmd).
+// The cache keeps DefaultMemoryProbeCacheSize media infos at most.
+var DefaultProber = NewProber(NewMemoryProbeCache(DefaultMemoryProbeCacheSize), false)
+
+// Key returns the cache key of the file by the absolute path, size and modification time(and content hash if enabled).
+func (p *Prober) Key(file string) (string, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return "", fmt.Errorf("filepath.Abs() error: %v", err)
+	}
+
+	fi, err := os.Stat(abs)
+	if err != nil {
+		return "", fmt.Errorf("os.Stat() error: %v", err)
+	}
+
+	key := fmt.Sprintf("%s|%d|%d", abs, fi.Size(), fi.ModTime().UnixNano())
+
+	if p.hash {
+		f, err := os.Open(abs)
+		if err != nil {
+			return "", fmt.Errorf("os.Open() error: %v", err)
+		}
+		defer f.Close()
+
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return "", fmt.Errorf("io.Copy() error: %v", err)
+		}
+		key += "|" + hex.EncodeToString(h.Sum(nil))
+	}
+
+	return key, nil
+}
+
+// Probe returns the media info of the file from the cache, or runs ffprobe and caches the media info.
+// ctx: context to cancel ffprobe.
+// file: media file.
+func (p *Prober) Probe(ctx context.Context, file string) (*MediaInfo, error) {
+	if p.cache == nil {
+		return Probe(ctx, file)
+	}
+
+	key, err := p.Key(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if mi, ok := p.cache.Get(key); ok {
+		return mi, nil
+	}
+
+	mi, err := Probe(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.cache.Set(key, mi); err != nil {
+		return nil, fmt.Errorf("cache.Set() error: %v", err)
+	}
+
+	return mi, nil
+}
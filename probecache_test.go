@@ -0,0 +1,132 @@
+package ffcmd_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/northbright/ffcmd"
+)
+
+func ExampleProber() {
+	dir, err := os.MkdirTemp("", "ffcmd")
+	if err != nil {
+		log.Printf("os.MkdirTemp() error: %v", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	// Create a dummy media file.
+	file := filepath.Join(dir, "01.MOV")
+	if err := os.WriteFile(file, []byte("dummy"), 0644); err != nil {
+		log.Printf("os.WriteFile() error: %v", err)
+		return
+	}
+
+	// Store the cache in a JSON file.
+	cacheFile := filepath.Join(dir, "probe_cache.json")
+	cache, err := ffcmd.NewFileProbeCache(cacheFile)
+	if err != nil {
+		log.Printf("ffcmd.NewFileProbeCache() error: %v", err)
+		return
+	}
+
+	prober := ffcmd.NewProber(cache, true)
+
+	// Fill the cache as if the file was probed.
+	key, err := prober.Key(file)
+	if err != nil {
+		log.Printf("prober.Key() error: %v", err)
+		return
+	}
+
+	mi := &ffcmd.MediaInfo{
+		Format:  ffcmd.Format{Filename: file, Duration: 12.33},
+		Streams: []ffcmd.Stream{{Index: 0, CodecType: "video", Width: 1920, Height: 1080}},
+	}
+	if err := cache.Set(key, mi); err != nil {
+		log.Printf("cache.Set() error: %v", err)
+		return
+	}
+
+	// Load the cache from the JSON file again, e.g. in next run.
+	if cache, err = ffcmd.NewFileProbeCache(cacheFile); err != nil {
+		log.Printf("ffcmd.NewFileProbeCache() error: %v", err)
+		return
+	}
+	prober = ffcmd.NewProber(cache, true)
+
+	// Media info is returned from the cache without running ffprobe.
+	if mi, err = prober.Probe(context.Background(), file); err != nil {
+		log.Printf("prober.Probe() error: %v", err)
+		return
+	}
+
+	fmt.Printf("duration: %.2f, video: %dx%d\n", mi.Format.Duration, mi.Streams[0].Width, mi.Streams[0].Height)
+
+	// Modifying the file invalidates the cache and it'll be probed again.
+	if err := os.WriteFile(file, []byte("modified"), 0644); err != nil {
+		log.Printf("os.WriteFile() error: %v", err)
+		return
+	}
+
+	newKey, _ := prober.Key(file)
+	_, ok := cache.Get(newKey)
+	fmt.Printf("cached after modified: %v\n", ok)
+
+	// Output:
+	// duration: 12.33, video: 1920x1080
+	// cached after modified: false
+}
+
+func ExampleMemoryProbeCache() {
+	// Keep 2 media infos at most.
+	// Keys are generated by Prober.Key() in real use.
+	cache := ffcmd.NewMemoryProbeCache(2)
+
+	// Miss: Prober runs ffprobe and stores the media info.
+	_, ok := cache.Get("01.MOV")
+	fmt.Printf("01.MOV cached: %v\n", ok)
+
+	mi := &ffcmd.MediaInfo{
+		Format:  ffcmd.Format{Filename: "01.MOV", Duration: 12.33, Tags: map[string]string{"title": "01"}},
+		Streams: []ffcmd.Stream{{Index: 0, CodecType: "video", Width: 1920, Height: 1080}},
+	}
+	if err := cache.Set("01.MOV", mi); err != nil {
+		log.Printf("cache.Set() error: %v", err)
+		return
+	}
+
+	// Hit: it returns a copy of the media info.
+	cached, ok := cache.Get("01.MOV")
+	fmt.Printf("01.MOV cached: %v, duration: %.2f\n", ok, cached.Format.Duration)
+
+	// Modifying the copy does not change the cache.
+	cached.Format.Tags["title"] = "modified"
+	cached.Streams[0].Width = 0
+
+	cached, _ = cache.Get("01.MOV")
+	fmt.Printf("title: %s, width: %d\n", cached.Format.Tags["title"], cached.Streams[0].Width)
+
+	// 01.MOV is used more recently than 02.MOV and 02.MOV is evicted when 03.MOV is stored.
+	cache.Set("02.MOV", &ffcmd.MediaInfo{Format: ffcmd.Format{Filename: "02.MOV"}})
+	cache.Get("01.MOV")
+	cache.Set("03.MOV", &ffcmd.MediaInfo{Format: ffcmd.Format{Filename: "03.MOV"}})
+
+	for _, file := range []string{"01.MOV", "02.MOV", "03.MOV"} {
+		_, ok := cache.Get(file)
+		fmt.Printf("%s cached: %v\n", file, ok)
+	}
+	fmt.Printf("len: %d\n", cache.Len())
+
+	// Output:
+	// 01.MOV cached: false
+	// 01.MOV cached: true, duration: 12.33
+	// title: 01, width: 1920
+	// 01.MOV cached: true
+	// 02.MOV cached: false
+	// 03.MOV cached: true
+	// len: 2
+}
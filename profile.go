@@ -0,0 +1,151 @@
+package ffcmd
+
+import (
+	"fmt"
+)
+
+// Profile represents the output profile.
+// Segments to concatenate(e.g. phone videos, screen recordings and images) must have identical resolution, SAR, frame rate, pixel format,
+// sample rate and channel layout. Use Normalize() to conform the segments to the profile.
+type Profile struct {
+	// W, H are the width and height of output video.
+	W int
+	H int
+	// FPS is the frame rate of output video.
+	FPS int
+	// Fit is the mode to fit video into the resolution. Default is FitPad.
+	Fit FitMode
+	// PadColor is the color to pad for FitPad. Empty means default(black).
+	PadColor string
+	// PixFmt is the pixel format of output video. Empty means default("yuv420p").
+	PixFmt string
+	// SampleRate is the sample rate of output audio. 0 means default(48000).
+	SampleRate int
+	// ChannelLayout is the channel layout of output audio(e.g. "mono", "stereo"). Empty means default("stereo").
+	ChannelLayout string
+}
+
+// validate checks if the profile is valid.
+func (p *Profile) validate() error {
+	if p.W <= 0 || p.H <= 0 {
+		return fmt.Errorf("invalid resolution")
+	}
+
+	if p.FPS <= 0 {
+		return fmt.Errorf("invalid frame rate")
+	}
+
+	if p.SampleRate < 0 {
+		return fmt.Errorf("invalid sample rate")
+	}
+
+	return nil
+}
+
+// pixFmt returns the pixel format of the profile or the default one.
+func (p *Profile) pixFmt() string {
+	if p.PixFmt == "" {
+		return "yuv420p"
+	}
+	return p.PixFmt
+}
+
+// sampleRate returns the sample rate of the profile or the default one.
+func (p *Profile) sampleRate() int {
+	if p.SampleRate <= 0 {
+		return 48000
+	}
+	return p.SampleRate
+}
+
+// channelLayout returns the channel layout of the profile or the default one.
+func (p *Profile) channelLayout() string {
+	if p.ChannelLayout == "" {
+		return "stereo"
+	}
+	return p.ChannelLayout
+}
+
+// silentAudio returns the filter to generate silent audio with the sample rate and channel layout of the profile.
+// duration: duration of the silent audio in seconds.
+func (p *Profile) silentAudio(duration float32) string {
+	return fmt.Sprintf("aevalsrc=0:c=%s:s=%d:d=%s", p.channelLayout(), p.sampleRate(), formatSecond(duration))
+}
+
+// fit returns the options to fit video into the resolution of the profile.
+func (p *Profile) fit() *Fit {
+	return &Fit{W: p.W, H: p.H, Mode: p.Fit, PadColor: p.PadColor}
+}
+
+// conformVideo chains the filters to set SAR to 1:1, frame rate and pixel format of the profile.
+func (p *Profile) conformVideo(fc *FilterChain) {
+	fc.Chain("setsar=1:1")
+	fc.Chain(fmt.Sprintf("fps=%d", p.FPS))
+	fc.Chain(fmt.Sprintf("format=pix_fmts=%s", p.pixFmt()))
+}
+
+// NormalizeVideo chains the filters to conform the video stream to the profile.
+// It fits the video into the resolution by the fit mode, then sets SAR to 1:1, frame rate and pixel format.
+// FitBlur is not supported because it requires multiple filterchains, use FitVideo() instead.
+func (p *Profile) NormalizeVideo(fc *FilterChain) error {
+	if err := p.validate(); err != nil {
+		return fmt.Errorf("invalid profile: %v", err)
+	}
+
+	filters, err := p.fit().filters()
+	if err != nil {
+		return err
+	}
+
+	for _, filter := range filters {
+		fc.Chain(filter)
+	}
+
+	p.conformVideo(fc)
+	return nil
+}
+
+// FitVideo returns the filterchains to conform the video stream to the profile for all fit modes.
+// in, inID: filterchain of the video and the 0-based index of its output.
+// output: output label of the conformed video in the "[OUTPUT_LABEL]" format.
+// The last filterchain outputs the conformed video.
+func (p *Profile) FitVideo(in *FilterChain, inID int, output string) ([]*FilterChain, error) {
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("invalid profile: %v", err)
+	}
+
+	fcs, err := p.fit().FilterChains(in, inID, output)
+	if err != nil {
+		return nil, err
+	}
+
+	p.conformVideo(fcs[len(fcs)-1])
+	return fcs, nil
+}
+
+// NormalizeAudio chains the filters to conform the audio stream to the profile.
+// It resamples the audio to the sample rate, then sets the sample format to "fltp", the sample rate and channel layout.
+func (p *Profile) NormalizeAudio(fc *FilterChain) {
+	fc.Chain(fmt.Sprintf("aresample=%d", p.sampleRate()))
+	fc.Chain(fmt.Sprintf("aformat=sample_fmts=fltp:sample_rates=%d:channel_layouts=%s", p.sampleRate(), p.channelLayout()))
+}
+
+// Normalize chains the filters to conform the video and audio streams to the profile.
+// v, a: video and audio filterchains. Any of them can be nil.
+func (p *Profile) Normalize(v, a *FilterChain) error {
+	if err := p.validate(); err != nil {
+		return fmt.Errorf("invalid profile: %v", err)
+	}
+
+	if v != nil {
+		if err := p.NormalizeVideo(v); err != nil {
+			return err
+		}
+	}
+
+	if a != nil {
+		p.NormalizeAudio(a)
+	}
+
+	return nil
+}
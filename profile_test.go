@@ -0,0 +1,54 @@
+package ffcmd_test
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/northbright/ffcmd"
+)
+
+func ExampleProfile_Normalize() {
+	// Conform a phone video to 1080p 30fps.
+	profile := ffcmd.Profile{W: 1920, H: 1080, FPS: 30, SampleRate: 44100, ChannelLayout: "mono"}
+
+	ff := ffcmd.New("output.mp4", true)
+	id := ff.AddInput("phone.mp4")
+
+	v := ffcmd.NewFilterChain("[outv]")
+	v.AddInputByID(id, "v", 0)
+
+	a := ffcmd.NewFilterChain("[outa]")
+	a.AddInputByID(id, "a", 0)
+
+	if err := profile.Normalize(v, a); err != nil {
+		log.Printf("profile.Normalize() error: %v", err)
+		return
+	}
+
+	ff.Chain(v)
+	ff.Chain(a)
+	ff.MapByOutputs(v)
+	cmd, err := ff.String()
+	if err != nil {
+		log.Printf("ff.String() error: %v", err)
+		return
+	}
+	fmt.Printf("%s\n", cmd)
+
+	// Invalid profile.
+	invalid := ffcmd.Profile{W: 1920, H: 1080}
+	if err := invalid.Normalize(v, a); err != nil {
+		fmt.Printf("invalid.Normalize() error: %v\n", err)
+	}
+
+	// Output:
+	// echo "y" | ffmpeg \
+	// -i "phone.mp4" \
+	// -filter_complex " \
+	// [0:v:0]scale=1920:1080:force_original_aspect_ratio=decrease,pad=1920:1080:(ow-iw)/2:(oh-ih)/2,setsar=1:1,fps=30,format=pix_fmts=yuv420p[outv];
+	// [0:a:0]aresample=44100,aformat=sample_fmts=fltp:sample_rates=44100:channel_layouts=mono[outa]" \
+	// -map "[outa]" \
+	// -map "[outv]" \
+	// output.mp4
+	// invalid.Normalize() error: invalid profile: invalid frame rate
+}
@@ -0,0 +1,137 @@
+package ffcmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SceneChange represents a scene change detected by select filter.
+type SceneChange struct {
+	// Time is the timestamp of the first frame of the new scene.
+	Time *Timestamp
+	// Score is the scene change score between 0 and 1.
+	Score float64
+}
+
+// TimeRange represents a range of time(e.g. a scene, a silence interval).
+type TimeRange struct {
+	Start *Timestamp
+	End   *Timestamp
+}
+
+// Duration returns the duration of the range in seconds.
+func (r *TimeRange) Duration() float32 {
+	return float32(r.End.seconds() - r.Start.seconds())
+}
+
+// VideoClip returns the video clip of the file trimmed by the range to add to the timeline.
+func (r *TimeRange) VideoClip(file string) VideoClip {
+	return VideoClip{File: file, Start: r.Start.String(), End: r.End.String()}
+}
+
+// SceneDetectFilter returns the filters to select the frames of scene changes and print the pts_time and scores to stderr.
+// threshold: scene change score between 0 and 1 to select the frame. 0.3 - 0.4 is a good start.
+func SceneDetectFilter(threshold float32) (string, error) {
+	if threshold <= 0 || threshold > 1 {
+		return "", fmt.Errorf("invalid threshold: %g", threshold)
+	}
+
+	expr := Gt(Var("scene"), roundConst(threshold))
+	return fmt.Sprintf("select=%s,metadata=print", FormatExpr(expr)), nil
+}
+
+var (
+	ptsTimeRegexp    = regexp.MustCompile(`\bpts_time:(\S+)`)
+	sceneScoreRegexp = regexp.MustCompile(`lavfi\.scene_score=(\S+)`)
+)
+
+// ParseSceneDetectOutput parses the output(stderr) of ffmpeg with the filters returned by SceneDetectFilter().
+func ParseSceneDetectOutput(output string) ([]SceneChange, error) {
+	var (
+		changes []SceneChange
+		ts      *Timestamp
+	)
+
+	for _, line := range strings.Split(output, "\n") {
+		// metadata filter prints "frame:N pts:N pts_time:N" and the metadata of the frame in the following lines.
+		if m := ptsTimeRegexp.FindStringSubmatch(line); m != nil {
+			second, err := parseSecond("pts_time", m[1])
+			if err != nil {
+				return nil, err
+			}
+
+			if ts, err = NewTimestampFromSecond(float32(second)); err != nil {
+				return nil, fmt.Errorf("NewTimestampFromSecond() error: %v", err)
+			}
+			continue
+		}
+
+		if m := sceneScoreRegexp.FindStringSubmatch(line); m != nil {
+			if ts == nil {
+				return nil, fmt.Errorf("scene score without pts_time")
+			}
+
+			score, err := strconv.ParseFloat(m[1], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid scene score: %q", m[1])
+			}
+
+			changes = append(changes, SceneChange{Time: ts, Score: score})
+			ts = nil
+		}
+	}
+
+	return changes, nil
+}
+
+// DetectScenes runs ffmpeg to detect the scene changes of the first video stream of the input.
+// ctx: context to cancel ffmpeg.
+// dir: working dir to run ffmpeg.
+// input: input file.
+// threshold: scene change score between 0 and 1. See SceneDetectFilter().
+func DetectScenes(ctx context.Context, dir, input string, threshold float32) ([]SceneChange, error) {
+	filter, err := SceneDetectFilter(threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := runFFmpeg(ctx, dir, "-i", input, "-map", "0:v:0", "-vf", filter, "-f", "null", "-")
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseSceneDetectOutput(string(out))
+}
+
+// SceneRanges splits the time from 0 to duration into scenes at the scene changes.
+// changes: scene changes returned by DetectScenes() or ParseSceneDetectOutput().
+// duration: duration of the input in seconds. Use Prober to get it.
+// minDuration: min duration of scenes in seconds. Scene changes closer than it to the previous one or the end are skipped.
+func SceneRanges(changes []SceneChange, duration, minDuration float32) ([]TimeRange, error) {
+	end, err := NewTimestampFromSecond(duration)
+	if err != nil {
+		return nil, fmt.Errorf("NewTimestampFromSecond() error: %v", err)
+	}
+
+	var ranges []TimeRange
+	start := &Timestamp{}
+
+	for _, c := range changes {
+		t := c.Time.seconds()
+		if t-start.seconds() < float64(minDuration) || end.seconds()-t < float64(minDuration) {
+			continue
+		}
+
+		ranges = append(ranges, TimeRange{Start: start, End: c.Time})
+		start = c.Time
+	}
+
+	if end.seconds() > start.seconds() {
+		ranges = append(ranges, TimeRange{Start: start, End: end})
+	}
+
+	return ranges, nil
+}
@@ -0,0 +1,63 @@
+package ffcmd_test
+
+import (
+	"fmt"
+
+	"github.com/northbright/ffcmd"
+)
+
+func ExampleSceneRanges() {
+	filter, err := ffcmd.SceneDetectFilter(0.4)
+	if err != nil {
+		fmt.Printf("ffcmd.SceneDetectFilter() error: %v", err)
+		return
+	}
+	fmt.Printf("filter: %s\n", filter)
+
+	// Output(stderr) of:
+	// ffmpeg -i input.mp4 -map 0:v:0 -vf "select='gt(scene,0.4)',metadata=print" -f null -
+	// Use ffcmd.DetectScenes() to run ffmpeg and parse the output.
+	stderr := `Input #0, mov,mp4,m4a,3gp,3g2,mj2, from 'input.mp4':
+  Duration: 00:00:30.03, start: 0.000000, bitrate: 4512 kb/s
+[Parsed_metadata_1 @ 0x600002a4c000] frame:0    pts:96096   pts_time:3.2032
+[Parsed_metadata_1 @ 0x600002a4c000] lavfi.scene_score=0.512437
+[Parsed_metadata_1 @ 0x600002a4c000] frame:1    pts:105105  pts_time:3.5035
+[Parsed_metadata_1 @ 0x600002a4c000] lavfi.scene_score=0.437112
+[Parsed_metadata_1 @ 0x600002a4c000] frame:2    pts:381381  pts_time:12.7127
+[Parsed_metadata_1 @ 0x600002a4c000] lavfi.scene_score=0.864305
+[Parsed_metadata_1 @ 0x600002a4c000] frame:3    pts:891891  pts_time:29.7297
+[Parsed_metadata_1 @ 0x600002a4c000] lavfi.scene_score=0.405512`
+
+	changes, err := ffcmd.ParseSceneDetectOutput(stderr)
+	if err != nil {
+		fmt.Printf("ffcmd.ParseSceneDetectOutput() error: %v", err)
+		return
+	}
+
+	for _, c := range changes {
+		fmt.Printf("scene change: %s, score: %.3f\n", c.Time, c.Score)
+	}
+
+	// Split the input into scenes of 1 second at least.
+	ranges, err := ffcmd.SceneRanges(changes, 30.03, 1)
+	if err != nil {
+		fmt.Printf("ffcmd.SceneRanges() error: %v", err)
+		return
+	}
+
+	// Convert the scenes to video clips to add to the timeline.
+	for _, r := range ranges {
+		c := r.VideoClip("input.mp4")
+		fmt.Printf("scene: %s - %s, duration: %.3f\n", c.Start, c.End, r.Duration())
+	}
+
+	// Output:
+	// filter: select='gt(scene,0.4)',metadata=print
+	// scene change: 00:00:03.203, score: 0.512
+	// scene change: 00:00:03.503, score: 0.437
+	// scene change: 00:00:12.713, score: 0.864
+	// scene change: 00:00:29.730, score: 0.406
+	// scene: 00:00:00.000 - 00:00:03.203, duration: 3.203
+	// scene: 00:00:03.203 - 00:00:12.713, duration: 9.510
+	// scene: 00:00:12.713 - 00:00:30.030, duration: 17.317
+}
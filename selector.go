@@ -0,0 +1,131 @@
+package ffcmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// streamTypes maps the codec types of streams to the stream types used in stream specifiers.
+var streamTypes = map[string]string{
+	"video":      "v",
+	"audio":      "a",
+	"subtitle":   "s",
+	"data":       "d",
+	"attachment": "t",
+}
+
+// StreamSelector selects the stream of input by the probed properties instead of the index.
+// Zero values of the fields match any stream.
+type StreamSelector struct {
+	// Type is the stream type: "v" for video, "a" for audio, "s" for subtitles, "d" for data and "t" for attachments.
+	// "v" does not match attached pictures(e.g. cover art).
+	Type string
+	// Language is the language tag(e.g. "eng", "chi"). It's case-insensitive.
+	Language string
+	// Codec is the short name of codec(e.g. "aac", "subrip").
+	Codec string
+	// Channels is the number of audio channels.
+	Channels int
+	// Default, Forced match the streams with default / forced disposition.
+	Default bool
+	Forced  bool
+	// Title matches the title tag of stream.
+	Title *regexp.Regexp
+}
+
+// Match returns if the stream matches the selector.
+func (sel *StreamSelector) Match(s *Stream) bool {
+	if sel.Type != "" && streamTypes[s.CodecType] != sel.Type {
+		return false
+	}
+
+	// Attached pictures(e.g. cover art) are not video streams.
+	if sel.Type == "v" && !s.IsVideo() {
+		return false
+	}
+
+	if sel.Language != "" && !strings.EqualFold(s.Language, sel.Language) {
+		return false
+	}
+
+	if sel.Codec != "" && s.CodecName != sel.Codec {
+		return false
+	}
+
+	if sel.Channels > 0 && s.Channels != sel.Channels {
+		return false
+	}
+
+	if sel.Default && !s.Disposition["default"] {
+		return false
+	}
+
+	if sel.Forced && !s.Disposition["forced"] {
+		return false
+	}
+
+	if sel.Title != nil && !sel.Title.MatchString(s.Title) {
+		return false
+	}
+
+	return true
+}
+
+// Specifier returns the stream specifier of the first matched stream in the "INPUT_ID:TYPE:INDEX" format(e.g. "1:a:2").
+// It returns "INPUT_ID:INDEX" if the stream type is unknown.
+// inputID: 0-based input ID.
+// mi: media info of the input. Use Probe() or Prober to get it.
+func (sel *StreamSelector) Specifier(inputID int, mi *MediaInfo) (string, error) {
+	if mi == nil {
+		return "", fmt.Errorf("nil media info")
+	}
+
+	// Index of the stream in the streams of the same type.
+	typeIndexes := make(map[string]int)
+
+	for i, s := range mi.Streams {
+		t, ok := streamTypes[s.CodecType]
+
+		if sel.Match(&mi.Streams[i]) {
+			if !ok {
+				return fmt.Sprintf("%d:%d", inputID, s.Index), nil
+			}
+			return fmt.Sprintf("%d:%s:%d", inputID, t, typeIndexes[t]), nil
+		}
+
+		if ok {
+			typeIndexes[t]++
+		}
+	}
+
+	return "", fmt.Errorf("no stream matches the selector")
+}
+
+// AddInputBySelector adds the stream of input matched by the selector as the input(e.g. "[1:a:2]").
+// inputID: 0-based input ID.
+// mi: media info of the input. Use Probe() or Prober to get it.
+// sel: stream selector.
+func (fc *FilterChain) AddInputBySelector(inputID int, mi *MediaInfo, sel *StreamSelector) error {
+	spec, err := sel.Specifier(inputID, mi)
+	if err != nil {
+		return err
+	}
+
+	fc.AddInput(fmt.Sprintf("[%s]", spec))
+	return nil
+}
+
+// MapBySelector selects the stream of input matched by the selector as ffmpeg output.
+// inputID: 0-based input ID.
+// mi: media info of the input. Use Probe() or Prober to get it.
+// sel: stream selector.
+func (ff *FFmpeg) MapBySelector(inputID int, mi *MediaInfo, sel *StreamSelector) error {
+	spec, err := sel.Specifier(inputID, mi)
+	if err != nil {
+		return err
+	}
+
+	ff.Map(spec)
+	return nil
+}
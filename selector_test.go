@@ -0,0 +1,71 @@
+package ffcmd_test
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/northbright/ffcmd"
+)
+
+func ExampleStreamSelector() {
+	// Media info of a multi-language MKV with cover art. Use ffcmd.Probe() or ffcmd.Prober to get it.
+	mi := &ffcmd.MediaInfo{
+		Streams: []ffcmd.Stream{
+			{Index: 0, CodecType: "video", CodecName: "mjpeg", Disposition: map[string]bool{"attached_pic": true}},
+			{Index: 1, CodecType: "video", CodecName: "h264"},
+			{Index: 2, CodecType: "audio", CodecName: "ac3", Channels: 6, Language: "chi", Disposition: map[string]bool{"default": true}},
+			{Index: 3, CodecType: "audio", CodecName: "aac", Channels: 2, Language: "eng", Title: "Director's Commentary"},
+			{Index: 4, CodecType: "audio", CodecName: "aac", Channels: 2, Language: "eng", Title: "Stereo"},
+			{Index: 5, CodecType: "subtitle", CodecName: "subrip", Language: "eng", Disposition: map[string]bool{"forced": true}},
+			{Index: 6, CodecType: "subtitle", CodecName: "subrip", Language: "chi", Disposition: map[string]bool{"default": true}},
+		},
+	}
+
+	ff := ffcmd.New("output.mp4", true)
+	id := ff.AddInput("movie.mkv")
+
+	// English stereo audio track which is not the commentary.
+	eng := &ffcmd.StreamSelector{Type: "a", Language: "eng", Channels: 2, Title: regexp.MustCompile(`^Stereo$`)}
+
+	a := ffcmd.NewFilterChain("[outa]")
+	if err := a.AddInputBySelector(id, mi, eng); err != nil {
+		fmt.Printf("a.AddInputBySelector() error: %v", err)
+		return
+	}
+	a.Chain("volume=1.5")
+	ff.Chain(a)
+
+	// Video which is not the cover art and default subtitle track.
+	if err := ff.MapBySelector(id, mi, &ffcmd.StreamSelector{Type: "v"}); err != nil {
+		fmt.Printf("ff.MapBySelector() error: %v", err)
+		return
+	}
+
+	if err := ff.MapBySelector(id, mi, &ffcmd.StreamSelector{Type: "s", Default: true}); err != nil {
+		fmt.Printf("ff.MapBySelector() error: %v", err)
+		return
+	}
+
+	str, err := ff.String()
+	if err != nil {
+		fmt.Printf("ff.String() error: %v", err)
+		return
+	}
+	fmt.Println(str)
+
+	// Error when nothing matches.
+	if err := ff.MapBySelector(id, mi, &ffcmd.StreamSelector{Type: "a", Language: "jpn"}); err != nil {
+		fmt.Printf("ff.MapBySelector() error: %v\n", err)
+	}
+
+	// Output:
+	// echo "y" | ffmpeg \
+	// -i "movie.mkv" \
+	// -filter_complex " \
+	// [0:a:2]volume=1.5[outa]" \
+	// -map "0:s:1" \
+	// -map "0:v:1" \
+	// -map "[outa]" \
+	// output.mp4
+	// ff.MapBySelector() error: no stream matches the selector
+}
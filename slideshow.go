@@ -0,0 +1,229 @@
+package ffcmd
+
+import (
+	"fmt"
+	"math"
+)
+
+// Point represents a point relative to the size of image.
+// X and Y are in the range [0, 1]. e.g. {0.5, 0.5} is the center of image.
+type Point struct {
+	X float32
+	Y float32
+}
+
+// Slide represents an image in the slideshow.
+type Slide struct {
+	// File is the image file.
+	File string
+	// Duration is the duration of the slide in seconds.
+	Duration float32
+	// ZoomStart, ZoomEnd are the zoom factors at the start / end of the slide(e.g. 1.0 to 1.3). 0 means 1(no zoom).
+	ZoomStart float32
+	ZoomEnd   float32
+	// FocusStart, FocusEnd are the focal points at the start / end of the slide to pan. nil means the center of image.
+	FocusStart *Point
+	FocusEnd   *Point
+	// Caption is the caption text of the slide. Empty means no caption.
+	Caption string
+	// Transition is the transition from the previous slide. nil means the default transition of the slideshow.
+	Transition *Transition
+}
+
+// Slideshow represents a slideshow generated by images with pan / zoom motion(Ken Burns effect), transitions, captions and music.
+type Slideshow struct {
+	profile      Profile
+	transition   Transition
+	slides       []Slide
+	captionStyle *DrawText
+	bgm          *BGM
+}
+
+// NewSlideshow returns a new slideshow.
+// profile: output profile.
+// transition: default transition between slides.
+func NewSlideshow(profile Profile, transition Transition) *Slideshow {
+	return &Slideshow{profile: profile, transition: transition}
+}
+
+// AddSlide adds a slide at the end of the slideshow.
+func (s *Slideshow) AddSlide(slide Slide) {
+	s.slides = append(s.slides, slide)
+}
+
+// SetCaptionStyle sets the style of captions. The Text, TextFile, Start, End and Enable are ignored.
+// Default style is white text in a half-transparent box at the bottom.
+func (s *Slideshow) SetCaptionStyle(style *DrawText) {
+	s.captionStyle = style
+}
+
+// SetBGM sets the background music of the slideshow.
+func (s *Slideshow) SetBGM(bgm *BGM) {
+	s.bgm = bgm
+}
+
+// roundConst returns the constant expression of float32 value rounded to 3 decimal places.
+func roundConst(v float32) Expr {
+	return Const(math.Round(float64(v)*1000) / 1000)
+}
+
+// lerp returns the expression to interpolate linearly from start to end by progress.
+func lerp(start, end float32, progress Expr) Expr {
+	if start == end {
+		return roundConst(start)
+	}
+	return Add(roundConst(start), Mul(roundConst(end-start), progress))
+}
+
+// zoompanFilter returns the zoompan filter to generate the frames of the slide.
+func (s *Slideshow) zoompanFilter(slide *Slide, frames int) string {
+	p := s.profile
+
+	zs, ze := slide.ZoomStart, slide.ZoomEnd
+	if zs <= 0 {
+		zs = 1
+	}
+	if ze <= 0 {
+		ze = 1
+	}
+
+	fs, fe := slide.FocusStart, slide.FocusEnd
+	if fs == nil {
+		fs = &Point{0.5, 0.5}
+	}
+	if fe == nil {
+		fe = &Point{0.5, 0.5}
+	}
+
+	// Progress of the slide from 0 to 1 by the output frame number("on").
+	var progress Expr = Const(0)
+	if frames > 1 {
+		progress = Div(Var("on"), Const(float64(frames-1)))
+	}
+
+	iw, ih, zoom := Var("iw"), Var("ih"), Var("zoom")
+
+	// Center the visible area(iw/zoom x ih/zoom) at the focal point and keep it in the image.
+	x := Clip(Sub(Mul(lerp(fs.X, fe.X, progress), iw), Div(iw, Mul(zoom, Const(2)))), Const(0), Sub(iw, Div(iw, zoom)))
+	y := Clip(Sub(Mul(lerp(fs.Y, fe.Y, progress), ih), Div(ih, Mul(zoom, Const(2)))), Const(0), Sub(ih, Div(ih, zoom)))
+
+	return fmt.Sprintf("zoompan=z=%s:x=%s:y=%s:d=%d:s=%dx%d:fps=%d", FormatExpr(lerp(zs, ze, progress)), FormatExpr(x), FormatExpr(y), frames, p.W, p.H, p.FPS)
+}
+
+// caption returns the drawtext filter of the caption.
+func (s *Slideshow) caption(text string) (string, error) {
+	var dt DrawText
+
+	if s.captionStyle != nil {
+		dt = *s.captionStyle
+	} else {
+		dt = DrawText{
+			FontSize:   s.profile.H / 20,
+			FontColor:  "white",
+			Box:        true,
+			BoxColor:   "black@0.5",
+			BoxBorderW: 10,
+			Anchor:     AnchorBottom,
+			Margin:     s.profile.H / 20,
+		}
+	}
+
+	dt.Text = text
+	dt.TextFile = ""
+	dt.Start = ""
+	dt.End = ""
+	dt.Enable = nil
+
+	return dt.Filter()
+}
+
+// FFmpeg returns the ffmpeg command to render the slideshow.
+// output: ffmpeg output(e.g. "output.mp4")
+// overwrite: if overwrite output when run ffmpeg command.
+// The video stream is labeled "[outv]". The audio stream is labeled "[outa]" if background music is set.
+// Fit of the profile is ignored. Images are always scaled and cropped(FitCrop) to fill the frame for zoompan.
+func (s *Slideshow) FFmpeg(output string, overwrite bool) (*FFmpeg, error) {
+	p := s.profile
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("invalid profile: %v", err)
+	}
+
+	if len(s.slides) == 0 {
+		return nil, fmt.Errorf("no slides in the slideshow")
+	}
+
+	ff := New(output, overwrite)
+	seq := NewTransitionSequencer("[outv]", "[outa]", s.transition)
+
+	// Scale the image to 2x of output resolution before zoompan to reduce jitter.
+	// Always crop to fill the frame, zoompan pans / zooms the whole image and would move the borders of padding.
+	fit := &Fit{W: p.W * 2, H: p.H * 2, Mode: FitCrop}
+	fitFilters, err := fit.filters()
+	if err != nil {
+		return nil, fmt.Errorf("fit.filters() error: %v", err)
+	}
+
+	for i, slide := range s.slides {
+		if slide.Duration <= 0 {
+			return nil, fmt.Errorf("slide %d(%s): invalid duration", i, slide.File)
+		}
+
+		v := NewFilterChain(fmt.Sprintf("[slide_%02d_v]", i))
+		v.AddInputByID(ff.AddInput(slide.File), "v", 0)
+
+		for _, filter := range fitFilters {
+			v.Chain(filter)
+		}
+
+		frames := int(slide.Duration * float32(p.FPS))
+		v.Chain(s.zoompanFilter(&slide, frames))
+		p.conformVideo(v)
+
+		if slide.Caption != "" {
+			caption, err := s.caption(slide.Caption)
+			if err != nil {
+				return nil, fmt.Errorf("slide %d(%s): caption error: %v", i, slide.File, err)
+			}
+			v.Chain(caption)
+		}
+
+		ff.Chain(v)
+
+		if slide.Transition != nil {
+			seq.AddClipWithTransition(v, 0, nil, 0, slide.Duration, *slide.Transition)
+		} else {
+			seq.AddClip(v, 0, nil, 0, slide.Duration)
+		}
+	}
+
+	vfcs, _, err := seq.FilterChains()
+	if err != nil {
+		return nil, fmt.Errorf("seq.FilterChains() error: %v", err)
+	}
+
+	for _, fc := range vfcs {
+		ff.Chain(fc)
+	}
+
+	if s.bgm != nil {
+		duration := seq.Duration()
+
+		// Mix the music with silent audio of the slideshow's duration.
+		silent := NewFilterChain("[silent]")
+		silent.Chain(p.silentAudio(duration))
+		ff.Chain(silent)
+
+		fcs, err := s.bgm.FilterChains(ff, silent, 0, duration, "[outa]")
+		if err != nil {
+			return nil, fmt.Errorf("BGM: %v", err)
+		}
+
+		for _, fc := range fcs {
+			ff.Chain(fc)
+		}
+
+		ff.MapByOutput(vfcs[len(vfcs)-1], 0)
+	}
+
+	return ff, nil
+}
@@ -0,0 +1,57 @@
+package ffcmd_test
+
+import (
+	"fmt"
+
+	"github.com/northbright/ffcmd"
+)
+
+func ExampleSlideshow() {
+	s := ffcmd.NewSlideshow(ffcmd.Profile{W: 1280, H: 720, FPS: 25}, ffcmd.Transition{Type: ffcmd.TransitionFade, Duration: 1})
+
+	// Zoom in at the center.
+	s.AddSlide(ffcmd.Slide{File: "01.jpg", Duration: 4, ZoomStart: 1, ZoomEnd: 1.2, Caption: "Day 1"})
+
+	// Pan from left to right with a fixed zoom and slide in.
+	s.AddSlide(ffcmd.Slide{
+		File:       "02.jpg",
+		Duration:   4,
+		ZoomStart:  1.3,
+		ZoomEnd:    1.3,
+		FocusStart: &ffcmd.Point{X: 0.3, Y: 0.5},
+		FocusEnd:   &ffcmd.Point{X: 0.7, Y: 0.5},
+		Transition: &ffcmd.Transition{Type: ffcmd.TransitionSlideLeft, Duration: 0.5},
+	})
+
+	s.SetBGM(&ffcmd.BGM{File: "music.mp3", FadeOut: 2, Loop: true})
+
+	ffmpeg, err := s.FFmpeg("output.mp4", true)
+	if err != nil {
+		fmt.Printf("s.FFmpeg() error: %v", err)
+		return
+	}
+
+	str, err := ffmpeg.String()
+	if err != nil {
+		fmt.Printf("ffmpeg.String() error: %v", err)
+		return
+	}
+
+	fmt.Println(str)
+
+	// Output:
+	// echo "y" | ffmpeg \
+	// -i "01.jpg" \
+	// -i "02.jpg" \
+	// -i "music.mp3" \
+	// -filter_complex " \
+	// [0:v:0]scale=2560:1440:force_original_aspect_ratio=increase,crop=2560:1440,zoompan=z=1+0.2*on/99:x='clip(0.5*iw-iw/(zoom*2),0,iw-iw/zoom)':y='clip(0.5*ih-ih/(zoom*2),0,ih-ih/zoom)':d=100:s=1280x720:fps=25,setsar=1:1,fps=25,format=pix_fmts=yuv420p,drawtext=text=Day 1:expansion=none:fontsize=36:fontcolor=white:box=1:boxcolor=black@0.5:boxborderw=10:x=(w-text_w)/2:y=h-text_h-36[slide_00_v];
+	// [1:v:0]scale=2560:1440:force_original_aspect_ratio=increase,crop=2560:1440,zoompan=z=1.3:x='clip((0.3+0.4*on/99)*iw-iw/(zoom*2),0,iw-iw/zoom)':y='clip(0.5*ih-ih/(zoom*2),0,ih-ih/zoom)':d=100:s=1280x720:fps=25,setsar=1:1,fps=25,format=pix_fmts=yuv420p[slide_01_v];
+	// [slide_00_v][slide_01_v]xfade=transition=slideleft:duration=0.500:offset=3.500[outv];
+	// aevalsrc=0:c=stereo:s=48000:d=7.500[silent];
+	// [2:a:0]aloop=loop=-1:size=2e+09,atrim=duration=7.500,asetpts=PTS-STARTPTS,afade=t=out:st=5.500:d=2.000[outa_music];
+	// [silent][outa_music]amix=inputs=2:duration=first:dropout_transition=0:normalize=0[outa]" \
+	// -map "[outa]" \
+	// -map "[outv]" \
+	// output.mp4
+}
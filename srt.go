@@ -1,6 +1,7 @@
 package ffcmd
 
 import (
+	"context"
 	"fmt"
 	"strings"
 )
@@ -13,12 +14,14 @@ type CreateOneSubSRTCmd struct {
 	text      string
 	start     string
 	end       string
+	offset    string
+	prober    *Prober
 }
 
 // NewCreateOneSubSRTCmd returns a new command to create SRT file.
 // srtFile: filename of the SRT file.
 // videoFile: filename of video file to add subtitles.
-// When end is empty, it'll try to get the duration of the video as end time.
+// When end is empty, it's resolved by probing the duration of the video when running. See ResolvedString().
 // text: subtitle text.
 // start, end: timestamp in the SRT file.
 func NewCreateOneSubSRTCmd(srtFile, videoFile, text, start, end string) (*CreateOneSubSRTCmd, error) {
@@ -56,10 +59,100 @@ func NewCreateOneSubSRTCmdForImageClip(srtFile, text string, duration float32) (
 	return NewCreateOneSubSRTCmd(srtFile, "", text, start, end)
 }
 
+// SetOffset sets the start time of the clip in the video(e.g. the video is trimmed from the offset).
+// The end time resolved by probing is the duration of the video minus the offset.
+// offset: timestamp in the "HH:MM:SS(.mmm)" format.
+func (cmd *CreateOneSubSRTCmd) SetOffset(offset string) error {
+	if offset != "" {
+		if _, err := NewTimestamp(offset); err != nil {
+			return fmt.Errorf("invalid offset format")
+		}
+	}
+
+	cmd.offset = offset
+	return nil
+}
+
+// SetProber sets the prober to probe the video. Default is DefaultProber.
+func (cmd *CreateOneSubSRTCmd) SetProber(prober *Prober) {
+	cmd.prober = prober
+}
+
+// probeEnd returns the end time by probing the duration of the video minus the offset.
+func (cmd *CreateOneSubSRTCmd) probeEnd(ctx context.Context, dir string) (string, error) {
+	if cmd.videoFile == "" {
+		return "", fmt.Errorf("both end time and video filename are empty, can not get end timestamp")
+	}
+
+	prober := cmd.prober
+	if prober == nil {
+		prober = DefaultProber
+	}
+
+	mi, err := prober.Probe(ctx, resolvePath(dir, cmd.videoFile))
+	if err != nil {
+		return "", fmt.Errorf("probe %s error: %v", cmd.videoFile, err)
+	}
+
+	// Use the duration of the first video stream, or the container if it's unknown.
+	var duration float64
+	if streams := mi.VideoStreams(); len(streams) > 0 {
+		duration = streams[0].Duration
+	}
+	if duration <= 0 {
+		duration = mi.Format.Duration
+	}
+	if duration <= 0 {
+		return "", fmt.Errorf("unknown duration of %s", cmd.videoFile)
+	}
+
+	if cmd.offset != "" {
+		offset, err := NewTimestamp(cmd.offset)
+		if err != nil {
+			return "", fmt.Errorf("invalid offset format")
+		}
+		duration -= offset.seconds()
+	}
+
+	if duration <= 0 {
+		return "", fmt.Errorf("offset %s exceeds the duration of %s", cmd.offset, cmd.videoFile)
+	}
+
+	ts, err := NewTimestampFromSecond(float32(duration))
+	if err != nil {
+		return "", fmt.Errorf("NewTimestampFromSecond() error: %v", err)
+	}
+
+	return ts.String(), nil
+}
+
+// ResolvedString returns the command string with the end time resolved by probing the duration of the video in Go when end time is empty.
+// The command is not changed, the end time is probed every time it's called.
+// ctx: context to cancel ffprobe.
+// dir: working dir to find the video file. It should be the same dir passed to Run().
+func (cmd *CreateOneSubSRTCmd) ResolvedString(ctx context.Context, dir string) (string, error) {
+	if cmd.end != "" {
+		return cmd.String()
+	}
+
+	end, err := cmd.probeEnd(ctx, dir)
+	if err != nil {
+		return "", err
+	}
+
+	return cmd.string(end)
+}
+
 // String returns the command string to run.
+// When end time is empty, the end time is got by running ffprobe in bash.
+// Run() and ResolvedString() probe the duration in Go instead and report the error if probing fails.
 func (cmd *CreateOneSubSRTCmd) String() (string, error) {
-	var start, end string
-	str := ""
+	return cmd.string(cmd.end)
+}
+
+// string returns the command string with the end time. Empty end time is got by running ffprobe in bash.
+func (cmd *CreateOneSubSRTCmd) string(end string) (string, error) {
+	var start string
 
 	if cmd.start == "" {
 		start = "00:00:00,000"
@@ -72,29 +165,37 @@ func (cmd *CreateOneSubSRTCmd) String() (string, error) {
 		start = ts.StringForSRT()
 	}
 
-	if cmd.end == "" {
+	if end == "" {
 		if cmd.videoFile == "" {
 			return "", fmt.Errorf("both end time and video filename are empty, can not get end timestamp")
 		}
 
-		str = fmt.Sprintf(`ffprobe -v error -select_streams v:0 -show_entries stream=duration -of csv=s=,:p=0 "%s" | awk -F. '{ print $1 }' | read sec; hh=$((sec / 3600)); mm=$((sec %% 3600 / 60)); ss=$((sec %% 3600 %% 60)); printf -v end "%%02d:%%02d:%%02d,000" hh mm ss; echo -ne "1\n%s --> $end\n%s" > "%s"`, cmd.videoFile, start, cmd.text, cmd.srtFile)
-	} else {
-		ts, err := NewTimestamp(cmd.end)
-		if err != nil {
-			return "", fmt.Errorf("invalid end time format")
+		// Duration of the video minus the offset in seconds.
+		duration := "$1 * 1000"
+		if cmd.offset != "" {
+			offset, err := NewTimestamp(cmd.offset)
+			if err != nil {
+				return "", fmt.Errorf("invalid offset format")
+			}
+			duration = fmt.Sprintf("($1 - %s) * 1000", offset.Second())
 		}
-		end = ts.StringForSRT()
 
-		str = fmt.Sprintf(`echo -ne "1\n%s --> %s\n%s" > "%s"`, start, end, cmd.text, cmd.srtFile)
+		return fmt.Sprintf(`end=$(ffprobe -v error -select_streams v:0 -show_entries stream=duration -of csv=p=0 "%s" | awk '{ ms = int(%s + 0.5); printf "%%02d:%%02d:%%02d,%%03d", ms / 3600000, ms / 60000 %% 60, ms / 1000 %% 60, ms %% 1000 }'); echo -ne "1\n%s --> $end\n%s" > "%s"`,
+			cmd.videoFile, duration, start, cmd.text, cmd.srtFile), nil
+	}
+
+	ts, err := NewTimestamp(end)
+	if err != nil {
+		return "", fmt.Errorf("invalid end time format")
 	}
 
-	return str, nil
+	return fmt.Sprintf(`echo -ne "1\n%s --> %s\n%s" > "%s"`, start, ts.StringForSRT(), cmd.text, cmd.srtFile), nil
 }
 
 func (cmd *CreateOneSubSRTCmd) Run(dir string, fn ReadOutputFunc) error {
-	str, err := cmd.String()
+	str, err := cmd.ResolvedString(context.Background(), dir)
 	if err != nil {
-		return fmt.Errorf("cmd.String() error: %v", err)
+		return fmt.Errorf("cmd.ResolvedString() error: %v", err)
 	}
 
 	return RunCmd(dir, str, fn)
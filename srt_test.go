@@ -0,0 +1,111 @@
+package ffcmd_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/northbright/ffcmd"
+)
+
+// fakeFFprobe creates the video files in a temp dir and a fake ffprobe in the PATH which reports the durations of them.
+// It returns the temp dir as the working dir and the function to clean up.
+// durations: key is the video file, value is the duration in seconds.
+func fakeFFprobe(durations map[string]string) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "ffcmd")
+	if err != nil {
+		return "", nil, err
+	}
+
+	oldPath := os.Getenv("PATH")
+	cleanup := func() {
+		os.Setenv("PATH", oldPath)
+		os.RemoveAll(dir)
+	}
+
+	var cases []string
+	for file, duration := range durations {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte("dummy"), 0644); err != nil {
+			cleanup()
+			return "", nil, err
+		}
+
+		cases = append(cases, fmt.Sprintf(`*/%s) echo '{"format": {"duration": "%s"}, "streams": [{"index": 0, "codec_type": "video", "duration": "%s"}]}';;`, file, duration, duration))
+	}
+
+	// ffprobe gets the file as the last argument.
+	script := fmt.Sprintf("#!/bin/bash\ncase \"${@: -1}\" in\n%s\n*) echo \"${@: -1}: No such file or directory\" >&2; exit 1;;\nesac\n", strings.Join(cases, "\n"))
+
+	bin := filepath.Join(dir, "bin")
+	if err := os.Mkdir(bin, 0755); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	if err := os.WriteFile(filepath.Join(bin, "ffprobe"), []byte(script), 0755); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	os.Setenv("PATH", bin+string(os.PathListSeparator)+oldPath)
+	return dir, cleanup, nil
+}
+
+func ExampleCreateOneSubSRTCmd_ResolvedString() {
+	dir, cleanup, err := fakeFFprobe(map[string]string{"01.MOV": "8.541"})
+	if err != nil {
+		log.Printf("fakeFFprobe() error: %v", err)
+		return
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Empty end time is resolved by probing the duration of video with millisecond precision.
+	cmd, _ := ffcmd.NewCreateOneSubSRTCmd("01.srt", "01.MOV", "Hello", "", "")
+	str, err := cmd.ResolvedString(ctx, dir)
+	if err != nil {
+		log.Printf("cmd.ResolvedString() error: %v", err)
+		return
+	}
+	fmt.Println(str)
+
+	// The clip is trimmed from the offset.
+	cmd.SetOffset("00:00:02.500")
+	str, err = cmd.ResolvedString(ctx, dir)
+	if err != nil {
+		log.Printf("cmd.ResolvedString() error: %v", err)
+		return
+	}
+	fmt.Println(str)
+
+	// The offset exceeds the duration.
+	cmd.SetOffset("00:00:09")
+	if _, err := cmd.ResolvedString(ctx, dir); err != nil {
+		fmt.Printf("cmd.ResolvedString() error: %v\n", err)
+	}
+
+	// The command is not changed by resolving. String() gets the end time by running ffprobe in bash.
+	cmd.SetOffset("")
+	str, err = cmd.String()
+	if err != nil {
+		log.Printf("cmd.String() error: %v", err)
+		return
+	}
+	fmt.Println(str)
+
+	// Probing fails.
+	missing, _ := ffcmd.NewCreateOneSubSRTCmd("02.srt", "02.MOV", "Hello", "", "")
+	_, err = missing.ResolvedString(ctx, dir)
+	fmt.Printf("probe missing video failed: %v\n", err != nil)
+
+	// Output:
+	// echo -ne "1\n00:00:00,000 --> 00:00:08,541\nHello" > "01.srt"
+	// echo -ne "1\n00:00:00,000 --> 00:00:06,041\nHello" > "01.srt"
+	// cmd.ResolvedString() error: offset 00:00:09 exceeds the duration of 01.MOV
+	// end=$(ffprobe -v error -select_streams v:0 -show_entries stream=duration -of csv=p=0 "01.MOV" | awk '{ ms = int($1 * 1000 + 0.5); printf "%02d:%02d:%02d,%03d", ms / 3600000, ms / 60000 % 60, ms / 1000 % 60, ms % 1000 }'); echo -ne "1\n00:00:00,000 --> $end\nHello" > "01.srt"
+	// probe missing video failed: true
+}
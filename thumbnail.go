@@ -0,0 +1,178 @@
+package ffcmd
+
+import (
+	"fmt"
+)
+
+// NewThumbnailCmd returns the ffmpeg command to extract a single thumbnail(poster frame) at the timestamp.
+// input: video file.
+// ts: timestamp of the thumbnail. It seeks the input before decoding to extract the thumbnail quickly.
+// width: width of the thumbnail. The height is scaled to keep the aspect ratio. 0 means the original size.
+// output: image file(e.g. "poster.jpg").
+// overwrite: if overwrite output when run ffmpeg command.
+func NewThumbnailCmd(input string, ts *Timestamp, width int, output string, overwrite bool) (*FFmpeg, error) {
+	if ts == nil {
+		return nil, fmt.Errorf("nil timestamp")
+	}
+
+	if width < 0 {
+		return nil, fmt.Errorf("invalid width")
+	}
+
+	ff := New(output, overwrite)
+	id := ff.AddInputWithOptions(input, "-ss", ts.String())
+
+	if width > 0 {
+		fc := NewFilterChain("[thumb]")
+		fc.AddInputByID(id, "v", 0)
+		fc.Chain(fmt.Sprintf("scale=%d:-2", width))
+		ff.Chain(fc)
+	} else {
+		ff.Map(fmt.Sprintf("%d:v:0", id))
+	}
+
+	// Write the only frame to the image file instead of an image sequence.
+	ff.AddOutputOptions("-update", "1", "-frames:v", "1")
+	return ff, nil
+}
+
+// thumbnailsFilterChain returns the filterchain to select n frames evenly spaced in the video and scale them.
+// The first frame is at the start of video and the interval between frames is duration / n.
+func thumbnailsFilterChain(inputID int, duration float32, n, width int, output string) (*FilterChain, error) {
+	if duration <= 0 {
+		return nil, fmt.Errorf("invalid duration")
+	}
+
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid number of thumbnails")
+	}
+
+	if width < 0 {
+		return nil, fmt.Errorf("invalid width")
+	}
+
+	// Select the first frame and the frames whose time since the previous selected frame reaches the interval.
+	prev := Var("prev_selected_t")
+	interval := roundConst(duration / float32(n))
+	sel := Add(Call("isnan", prev), Gte(Sub(VarT, prev), interval))
+
+	fc := NewFilterChain(output)
+	fc.AddInputByID(inputID, "v", 0)
+	fc.Chain(fmt.Sprintf("select=%s", FormatExpr(sel)))
+
+	if width > 0 {
+		fc.Chain(fmt.Sprintf("scale=%d:-2", width))
+	}
+
+	return fc, nil
+}
+
+// NewThumbnailsCmd returns the ffmpeg command to extract n evenly spaced thumbnails.
+// input: video file.
+// duration: duration of the video in seconds.
+// n: number of thumbnails.
+// width: width of the thumbnails. The height is scaled to keep the aspect ratio. 0 means the original size.
+// output: image sequence pattern(e.g. "thumb_%03d.jpg").
+// overwrite: if overwrite output when run ffmpeg command.
+func NewThumbnailsCmd(input string, duration float32, n, width int, output string, overwrite bool) (*FFmpeg, error) {
+	ff := New(output, overwrite)
+	id := ff.AddInput(input)
+
+	fc, err := thumbnailsFilterChain(id, duration, n, width, "[thumbs]")
+	if err != nil {
+		return nil, err
+	}
+	ff.Chain(fc)
+
+	// Output selected frames only without duplicating.
+	ff.AddOutputOptions("-fps_mode", "vfr", "-frames:v", fmt.Sprintf("%d", n))
+	return ff, nil
+}
+
+// ContactSheet represents the options of a contact sheet which tiles thumbnails of the video in one image.
+type ContactSheet struct {
+	// Columns, Rows are the number of thumbnails in each row / column.
+	Columns int
+	Rows    int
+	// Width is the width of each thumbnail. The height is scaled to keep the aspect ratio. 0 means the original size.
+	Width int
+	// Padding is the space between thumbnails in pixels.
+	Padding int
+	// Margin is the space around the thumbnails in pixels.
+	Margin int
+	// Color is the color of the unused area(e.g. "black", "white"). Empty means default(black).
+	Color string
+	// Timecode draws the timecode of each thumbnail at the bottom right corner.
+	Timecode bool
+	// FontFile is the font file of timecode. Empty means default.
+	FontFile string
+	// FontSize is the font size of timecode. 0 means default(16).
+	FontSize int
+}
+
+// FFmpeg returns the ffmpeg command to generate the contact sheet.
+// input: video file.
+// duration: duration of the video in seconds.
+// output: image file(e.g. "contact_sheet.jpg").
+// overwrite: if overwrite output when run ffmpeg command.
+func (cs *ContactSheet) FFmpeg(input string, duration float32, output string, overwrite bool) (*FFmpeg, error) {
+	if cs.Columns <= 0 || cs.Rows <= 0 {
+		return nil, fmt.Errorf("invalid layout")
+	}
+
+	if cs.Padding < 0 || cs.Margin < 0 {
+		return nil, fmt.Errorf("negative padding or margin")
+	}
+
+	ff := New(output, overwrite)
+	id := ff.AddInput(input)
+
+	fc, err := thumbnailsFilterChain(id, duration, cs.Columns*cs.Rows, cs.Width, "[sheet]")
+	if err != nil {
+		return nil, err
+	}
+
+	if cs.Timecode {
+		fontSize := cs.FontSize
+		if fontSize <= 0 {
+			fontSize = 16
+		}
+
+		// Timestamps are kept after select filter.
+		dt := &DrawText{
+			Text:       "%{pts:hms}",
+			Expand:     true,
+			FontFile:   cs.FontFile,
+			FontSize:   fontSize,
+			FontColor:  "white",
+			Box:        true,
+			BoxColor:   "black@0.5",
+			BoxBorderW: 4,
+			Anchor:     AnchorBottomRight,
+			Margin:     8,
+		}
+
+		filter, err := dt.Filter()
+		if err != nil {
+			return nil, fmt.Errorf("dt.Filter() error: %v", err)
+		}
+		fc.Chain(filter)
+	}
+
+	tile := fmt.Sprintf("tile=%dx%d", cs.Columns, cs.Rows)
+	if cs.Padding > 0 {
+		tile += fmt.Sprintf(":padding=%d", cs.Padding)
+	}
+	if cs.Margin > 0 {
+		tile += fmt.Sprintf(":margin=%d", cs.Margin)
+	}
+	if cs.Color != "" {
+		tile += ":color=" + cs.Color
+	}
+	fc.Chain(tile)
+
+	ff.Chain(fc)
+	// Write the only frame to the image file instead of an image sequence.
+	ff.AddOutputOptions("-update", "1", "-frames:v", "1")
+	return ff, nil
+}
@@ -0,0 +1,72 @@
+package ffcmd_test
+
+import (
+	"fmt"
+
+	"github.com/northbright/ffcmd"
+)
+
+func ExampleContactSheet() {
+	// Extract the poster frame at 00:00:05.
+	ts, _ := ffcmd.NewTimestamp("00:00:05.000")
+	ff, err := ffcmd.NewThumbnailCmd("input.mp4", ts, 640, "poster.jpg", true)
+	if err != nil {
+		fmt.Printf("ffcmd.NewThumbnailCmd() error: %v", err)
+		return
+	}
+
+	str, err := ff.String()
+	if err != nil {
+		fmt.Printf("ff.String() error: %v", err)
+		return
+	}
+	fmt.Println(str)
+
+	// Extract 10 evenly spaced thumbnails of a 120-second video.
+	if ff, err = ffcmd.NewThumbnailsCmd("input.mp4", 120, 10, 320, "thumb_%03d.jpg", true); err != nil {
+		fmt.Printf("ffcmd.NewThumbnailsCmd() error: %v", err)
+		return
+	}
+
+	if str, err = ff.String(); err != nil {
+		fmt.Printf("ff.String() error: %v", err)
+		return
+	}
+	fmt.Println(str)
+
+	// Tile 4x3 thumbnails with timecode in one image.
+	cs := &ffcmd.ContactSheet{Columns: 4, Rows: 3, Width: 320, Padding: 4, Margin: 8, Timecode: true}
+	if ff, err = cs.FFmpeg("input.mp4", 120, "contact_sheet.jpg", true); err != nil {
+		fmt.Printf("cs.FFmpeg() error: %v", err)
+		return
+	}
+
+	if str, err = ff.String(); err != nil {
+		fmt.Printf("ff.String() error: %v", err)
+		return
+	}
+	fmt.Println(str)
+
+	// Output:
+	// echo "y" | ffmpeg \
+	// -ss 00:00:05.000 -i "input.mp4" \
+	// -filter_complex " \
+	// [0:v:0]scale=640:-2[thumb]" \
+	// -map "[thumb]" \
+	// -update 1 -frames:v 1 \
+	// poster.jpg
+	// echo "y" | ffmpeg \
+	// -i "input.mp4" \
+	// -filter_complex " \
+	// [0:v:0]select='isnan(prev_selected_t)+gte(t-prev_selected_t,12)',scale=320:-2[thumbs]" \
+	// -map "[thumbs]" \
+	// -fps_mode vfr -frames:v 10 \
+	// thumb_%03d.jpg
+	// echo "y" | ffmpeg \
+	// -i "input.mp4" \
+	// -filter_complex " \
+	// [0:v:0]select='isnan(prev_selected_t)+gte(t-prev_selected_t,10)',scale=320:-2,drawtext=text=%{pts\\\\:hms}:fontsize=16:fontcolor=white:box=1:boxcolor=black@0.5:boxborderw=4:x=w-text_w-8:y=h-text_h-8,tile=4x3:padding=4:margin=8[sheet]" \
+	// -map "[sheet]" \
+	// -update 1 -frames:v 1 \
+	// contact_sheet.jpg
+}
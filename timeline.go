@@ -0,0 +1,465 @@
+package ffcmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ImageClip represents a clip generated by an image.
+type ImageClip struct {
+	// File is the image file.
+	File string
+	// Duration is the duration of the clip in seconds.
+	Duration float32
+	// FadeOutDuration is the duration of fade-out at the end of the clip in seconds. 0 means no fade-out.
+	FadeOutDuration float32
+	// Subtitle is the subtitle text shown during the whole clip. Empty means no subtitle.
+	Subtitle string
+	// FontSize is the font size of subtitle. 0 means default.
+	FontSize int
+	// Chapter is the title of the chapter of the clip. Empty means default("Chapter N").
+	Chapter string
+}
+
+// VideoClip represents a clip of a video file.
+type VideoClip struct {
+	// File is the video file.
+	File string
+	// Start, End are the timestamps in the "HH:MM:SS(.mmm)" format to trim the video.
+	// Empty start means the beginning and empty end means the end of the video.
+	Start string
+	End   string
+	// Subtitle is the subtitle text shown during the whole clip. Empty means no subtitle.
+	Subtitle string
+	// FontSize is the font size of subtitle. 0 means default.
+	FontSize int
+	// Chapter is the title of the chapter of the clip. Empty means default("Chapter N").
+	Chapter string
+	// NoAudio indicates the video has no audio stream and silent audio is generated for the clip.
+	// It's detected automatically only if probing is enabled. See Timeline.EnableProbing().
+	NoAudio bool
+}
+
+// Timeline represents a project to concatenate image and video clips into one output.
+// It generates the ffmpeg inputs, filterchains, pre / post commands to create / remove SRT files and concat filter automatically.
+// Video clips without audio stream are detected only if probing is enabled by EnableProbing(),
+// otherwise set NoAudio of the clips or concat filter fails because of the missing audio streams.
+type Timeline struct {
+	profile  Profile
+	clips    []any
+	probing  bool
+	dir      string
+	prober   *Prober
+	bgm      *BGM
+	tags     map[string]string
+	chapters bool
+}
+
+// NewTimeline returns a new timeline.
+// profile: output profile.
+func NewTimeline(profile Profile) *Timeline {
+	return &Timeline{profile: profile, clips: []any{}}
+}
+
+// AddImageClip adds an image clip at the end of the timeline.
+func (tl *Timeline) AddImageClip(c ImageClip) {
+	tl.clips = append(tl.clips, c)
+}
+
+// AddVideoClip adds a video clip at the end of the timeline.
+func (tl *Timeline) AddVideoClip(c VideoClip) {
+	tl.clips = append(tl.clips, c)
+}
+
+// EnableProbing enables probing the clip files by ffprobe to detect media info(e.g. if the video has audio streams).
+// dir: working dir to find the clip files. It should be the same dir passed to Run().
+func (tl *Timeline) EnableProbing(dir string) {
+	tl.probing = true
+	tl.dir = dir
+}
+
+// SetProber sets the prober to probe the clip files. Default is DefaultProber which caches media info in memory.
+func (tl *Timeline) SetProber(prober *Prober) {
+	tl.prober = prober
+}
+
+// getProber returns the prober of the timeline or the default one.
+func (tl *Timeline) getProber() *Prober {
+	if tl.prober == nil {
+		return DefaultProber
+	}
+	return tl.prober
+}
+
+// SetBGM sets the background music mixed with the audio of clips.
+func (tl *Timeline) SetBGM(bgm *BGM) {
+	tl.bgm = bgm
+}
+
+// SetMetadata sets the container metadata and chapters of the output.
+// tags: global tags(e.g. "title", "artist", "comment"). nil means no tags.
+// chapters: if add a chapter at each clip boundary. It requires the durations of all clips.
+func (tl *Timeline) SetMetadata(tags map[string]string, chapters bool) {
+	tl.tags = tags
+	tl.chapters = chapters
+}
+
+// srtFileName returns a unique SRT filename for the clip file.
+// used: SRT filenames used by previous clips.
+func srtFileName(file string, id int, used map[string]struct{}) string {
+	name := strings.TrimSuffix(file, filepath.Ext(file))
+
+	srtFile := name + ".srt"
+	if _, ok := used[srtFile]; ok {
+		srtFile = fmt.Sprintf("%s_%02d.srt", name, id)
+	}
+
+	used[srtFile] = struct{}{}
+	return srtFile
+}
+
+// subtitlesFilter returns the subtitles filter string.
+func subtitlesFilter(srtFile string, fontSize int) string {
+	subtitles := "subtitles=" + EscapeFilterOptionValue(srtFile)
+	if fontSize > 0 {
+		subtitles += fmt.Sprintf(":force_style='Fontsize=%d'", fontSize)
+	}
+	return subtitles
+}
+
+// addSubtitles adds commands to create / remove the SRT file to ffmpeg and chains subtitles filter.
+func addSubtitles(ff *FFmpeg, fc *FilterChain, createCmd *CreateOneSubSRTCmd, fontSize int) error {
+	// Add command to create SRT file as ffmpeg's pre-commands(set-up commmands).
+	ff.AddPreCmd(createCmd)
+
+	removeCmd, err := NewRemoveOneSubSRTCmd(createCmd.srtFile)
+	if err != nil {
+		return fmt.Errorf("NewRemoveOneSubSRTCmd() error: %v", err)
+	}
+	// Add command to remove created file as ffmpeg's post-commands(clean-up commands).
+	ff.AddPostCmd(removeCmd)
+
+	fc.Chain(subtitlesFilter(createCmd.srtFile, fontSize))
+	return nil
+}
+
+// newClipVideoFilterChain returns the video filterchain of the clip.
+// For FitBlur, it outputs the source video to fit.
+func (tl *Timeline) newClipVideoFilterChain(id int) *FilterChain {
+	if tl.profile.Fit == FitBlur {
+		return NewFilterChain(fmt.Sprintf("[clip_%02d_src]", id))
+	}
+	return NewFilterChain(fmt.Sprintf("[clip_%02d_v]", id))
+}
+
+// normalizeClipVideo conforms the video of the clip to the profile.
+// It returns all the video filterchains of the clip. The last one outputs "[clip_XX_v]" to chain more filters.
+func (tl *Timeline) normalizeClipVideo(v *FilterChain, id int) ([]*FilterChain, error) {
+	p := tl.profile
+
+	if p.Fit != FitBlur {
+		if err := p.NormalizeVideo(v); err != nil {
+			return nil, fmt.Errorf("p.NormalizeVideo() error: %v", err)
+		}
+		return []*FilterChain{v}, nil
+	}
+
+	fcs, err := p.FitVideo(v, 0, fmt.Sprintf("[clip_%02d_v]", id))
+	if err != nil {
+		return nil, fmt.Errorf("p.FitVideo() error: %v", err)
+	}
+	return append([]*FilterChain{v}, fcs...), nil
+}
+
+// imageClipFilterChains adds the image clip as ffmpeg input and returns its video filterchains and audio filterchain.
+// The last video filterchain outputs the video of the clip.
+func (tl *Timeline) imageClipFilterChains(ff *FFmpeg, id int, c *ImageClip, srtFiles map[string]struct{}) ([]*FilterChain, *FilterChain, error) {
+	if c.Duration <= 0 {
+		return nil, nil, fmt.Errorf("invalid duration")
+	}
+
+	if c.FadeOutDuration < 0 || c.FadeOutDuration > c.Duration {
+		return nil, nil, fmt.Errorf("invalid fade-out duration")
+	}
+
+	p := tl.profile
+	v := tl.newClipVideoFilterChain(id)
+	v.AddInputByID(ff.AddInput(c.File), "v", 0)
+
+	// Repeat the image to generate frames of the duration and set timestamps by the frame rate.
+	frames := int(c.Duration * float32(p.FPS))
+	setpts := Div(Var("N"), Mul(Const(float64(p.FPS)), VarTB))
+	v.Chain(fmt.Sprintf("loop=loop=%d:size=1", frames-1)).Chain("setpts=" + FormatExpr(setpts))
+
+	vfcs, err := tl.normalizeClipVideo(v, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	v = vfcs[len(vfcs)-1]
+
+	if c.Subtitle != "" {
+		createCmd, err := NewCreateOneSubSRTCmdForImageClip(srtFileName(c.File, id, srtFiles), c.Subtitle, c.Duration)
+		if err != nil {
+			return nil, nil, fmt.Errorf("NewCreateOneSubSRTCmdForImageClip() error: %v", err)
+		}
+
+		if err := addSubtitles(ff, v, createCmd, c.FontSize); err != nil {
+			return nil, nil, fmt.Errorf("addSubtitles() error: %v", err)
+		}
+	}
+
+	if c.FadeOutDuration > 0 {
+		v.Chain(fmt.Sprintf("fade=t=out:st=%s:d=%s", formatSecond(c.Duration-c.FadeOutDuration), formatSecond(c.FadeOutDuration)))
+	}
+
+	// Generate silent audio for the image.
+	a := NewFilterChain(fmt.Sprintf("[clip_%02d_a]", id))
+	a.Chain(p.silentAudio(c.Duration))
+	p.NormalizeAudio(a)
+
+	return vfcs, a, nil
+}
+
+// videoClipFilterChains adds the video clip as ffmpeg input and returns its video filterchains, audio filterchain and duration in seconds.
+// The last video filterchain outputs the video of the clip. The duration is 0 if it's unknown.
+func (tl *Timeline) videoClipFilterChains(ff *FFmpeg, id int, c *VideoClip, srtFiles map[string]struct{}) ([]*FilterChain, *FilterChain, float64, error) {
+	var start, end *Timestamp
+	var err error
+
+	if c.Start != "" {
+		if start, err = NewTimestamp(c.Start); err != nil {
+			return nil, nil, 0, fmt.Errorf("invalid start time format")
+		}
+	}
+
+	if c.End != "" {
+		if end, err = NewTimestamp(c.End); err != nil {
+			return nil, nil, 0, fmt.Errorf("invalid end time format")
+		}
+
+		if start != nil && end.seconds() <= start.seconds() {
+			return nil, nil, 0, fmt.Errorf("end time should be after start time")
+		}
+	}
+
+	noAudio := c.NoAudio
+
+	// Duration of the video file in seconds. 0 means unknown.
+	var fileDuration float64
+
+	if tl.probing {
+		mi, err := tl.getProber().Probe(context.Background(), resolvePath(tl.dir, c.File))
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("Probe() error: %v", err)
+		}
+
+		noAudio = noAudio || !mi.HasAudio()
+
+		// Use the duration of the first video stream, or the container if it's unknown.
+		if streams := mi.VideoStreams(); len(streams) > 0 {
+			fileDuration = streams[0].Duration
+		}
+		if fileDuration <= 0 {
+			fileDuration = mi.Format.Duration
+		}
+	}
+
+	p := tl.profile
+	inputID := ff.AddInput(c.File)
+
+	v := tl.newClipVideoFilterChain(id)
+	v.AddInputByID(inputID, "v", 0)
+
+	a := NewFilterChain(fmt.Sprintf("[clip_%02d_a]", id))
+
+	// Duration of the clip in seconds. 0 means unknown.
+	var duration float64
+
+	switch {
+	case end != nil:
+		duration = end.seconds()
+		// trim filter stops at the end of the file if the end time exceeds it.
+		if fileDuration > 0 && fileDuration < duration {
+			duration = fileDuration
+		}
+	case fileDuration > 0:
+		duration = fileDuration
+	}
+
+	if duration > 0 && start != nil {
+		duration -= start.seconds()
+		if duration <= 0 {
+			return nil, nil, 0, fmt.Errorf("start time exceeds the duration")
+		}
+	}
+
+	if noAudio {
+		// Generate silent audio of the clip's duration.
+		if duration == 0 {
+			return nil, nil, 0, fmt.Errorf("unknown duration of the clip without audio, set end time or enable probing")
+		}
+
+		a.Chain(p.silentAudio(float32(duration)))
+	} else {
+		a.AddInputByID(inputID, "a", 0)
+	}
+
+	// Trim the clip before other filters.
+	if start != nil || end != nil {
+		var opts []string
+		if start != nil {
+			opts = append(opts, "start="+start.Second())
+		}
+		if end != nil {
+			opts = append(opts, "end="+end.Second())
+		}
+
+		v.Chain("trim=" + strings.Join(opts, ":")).Chain("setpts=PTS-STARTPTS")
+		if !noAudio {
+			a.Chain("atrim=" + strings.Join(opts, ":")).Chain("asetpts=PTS-STARTPTS")
+		}
+	}
+
+	vfcs, err := tl.normalizeClipVideo(v, id)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	v = vfcs[len(vfcs)-1]
+
+	p.NormalizeAudio(a)
+
+	if c.Subtitle != "" {
+		// Timestamps of the trimmed clip start from 0.
+		srtEnd := ""
+		if duration > 0 {
+			ts, err := NewTimestampFromSecond(float32(duration))
+			if err != nil {
+				return nil, nil, 0, fmt.Errorf("NewTimestampFromSecond() error: %v", err)
+			}
+			srtEnd = ts.String()
+		}
+
+		createCmd, err := NewCreateOneSubSRTCmd(srtFileName(c.File, id, srtFiles), c.File, c.Subtitle, "", srtEnd)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("NewCreateOneSubSRTCmd() error: %v", err)
+		}
+
+		// The end time is resolved by probing the duration of the video minus the start time when running.
+		if err := createCmd.SetOffset(c.Start); err != nil {
+			return nil, nil, 0, fmt.Errorf("createCmd.SetOffset() error: %v", err)
+		}
+		createCmd.SetProber(tl.getProber())
+
+		if err := addSubtitles(ff, v, createCmd, c.FontSize); err != nil {
+			return nil, nil, 0, fmt.Errorf("addSubtitles() error: %v", err)
+		}
+	}
+
+	return vfcs, a, duration, nil
+}
+
+// FFmpeg returns the ffmpeg command to render the timeline.
+// output: ffmpeg output(e.g. "output.mp4")
+// overwrite: if overwrite output when run ffmpeg command.
+// The concatenated video and audio streams are labeled "[outv]" and "[outa]".
+// The audio mixed with background music is labeled "[outa_bgm]".
+func (tl *Timeline) FFmpeg(output string, overwrite bool) (*FFmpeg, error) {
+	if err := tl.profile.validate(); err != nil {
+		return nil, fmt.Errorf("invalid profile: %v", err)
+	}
+
+	if len(tl.clips) == 0 {
+		return nil, fmt.Errorf("no clips in the timeline")
+	}
+
+	ff := New(output, overwrite)
+	concatFC := NewFilterChain("[outv]", "[outa]")
+	srtFiles := make(map[string]struct{})
+
+	// Duration of the timeline in seconds. It's 0 if duration of any clip is unknown.
+	var duration float64
+	durationKnown := true
+	var chapters []Chapter
+
+	for i, clip := range tl.clips {
+		var vfcs []*FilterChain
+		var a *FilterChain
+		var d float64
+		var title string
+		var err error
+
+		switch c := clip.(type) {
+		case ImageClip:
+			if vfcs, a, err = tl.imageClipFilterChains(ff, i, &c, srtFiles); err != nil {
+				return nil, fmt.Errorf("clip %d(%s): %v", i, c.File, err)
+			}
+			d = float64(c.Duration)
+			title = c.Chapter
+		case VideoClip:
+			if vfcs, a, d, err = tl.videoClipFilterChains(ff, i, &c, srtFiles); err != nil {
+				return nil, fmt.Errorf("clip %d(%s): %v", i, c.File, err)
+			}
+			title = c.Chapter
+		default:
+			return nil, fmt.Errorf("clip %d: unsupported clip type", i)
+		}
+
+		if d == 0 {
+			durationKnown = false
+		}
+
+		if title == "" {
+			title = fmt.Sprintf("Chapter %d", i+1)
+		}
+		chapters = append(chapters, Chapter{Title: title, Start: float32(duration), End: float32(duration + d)})
+		duration += d
+
+		for _, fc := range vfcs {
+			ff.Chain(fc)
+		}
+		ff.Chain(a)
+
+		concatFC.AddInputByOutput(vfcs[len(vfcs)-1], 0)
+		concatFC.AddInputByOutput(a, 0)
+	}
+
+	concatFC.Chain(fmt.Sprintf("concat=n=%d:v=1:a=1", len(tl.clips)))
+	ff.Chain(concatFC)
+
+	if tl.bgm != nil {
+		if !durationKnown {
+			duration = 0
+		}
+
+		fcs, err := tl.bgm.FilterChains(ff, concatFC, 1, float32(duration), "[outa_bgm]")
+		if err != nil {
+			return nil, fmt.Errorf("BGM: %v", err)
+		}
+
+		for _, fc := range fcs {
+			ff.Chain(fc)
+		}
+
+		// Select concatenated video and mixed audio as output.
+		ff.MapByOutput(concatFC, 0)
+	}
+
+	if tl.tags != nil || tl.chapters {
+		m := &Metadata{Tags: tl.tags}
+
+		if tl.chapters {
+			if !durationKnown {
+				return nil, fmt.Errorf("chapters require the durations of all clips, set End of video clips or enable probing")
+			}
+			m.Chapters = chapters
+		}
+
+		if err := ff.SetMetadata(m, metadataFileName(output)); err != nil {
+			return nil, fmt.Errorf("ff.SetMetadata() error: %v", err)
+		}
+	}
+
+	return ff, nil
+}
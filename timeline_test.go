@@ -0,0 +1,98 @@
+package ffcmd_test
+
+import (
+	"fmt"
+
+	"github.com/northbright/ffcmd"
+)
+
+func ExampleTimeline() {
+	tl := ffcmd.NewTimeline(ffcmd.Profile{W: 720, H: 960, FPS: 30})
+
+	tl.AddImageClip(ffcmd.ImageClip{File: "op.jpg", Duration: 3, FadeOutDuration: 1, Subtitle: "Good Times with Maomi & Mimao", FontSize: 15})
+	tl.AddVideoClip(ffcmd.VideoClip{File: "01.MP4", End: "00:00:05", Subtitle: "Mido's tickling Mimao and he's enjoying...", FontSize: 13})
+	// Silent audio is generated for the clip without audio stream.
+	// Call tl.EnableProbing() to detect it automatically.
+	tl.AddVideoClip(ffcmd.VideoClip{File: "02.MOV", End: "00:00:04", NoAudio: true})
+	tl.AddVideoClip(ffcmd.VideoClip{File: "03.MOV", Start: "00:00:01", End: "00:00:09", Subtitle: "It's hard to brush Maomi's teeth...", FontSize: 13})
+	tl.AddImageClip(ffcmd.ImageClip{File: "ed.jpg", Duration: 3, FadeOutDuration: 1})
+
+	ffmpeg, err := tl.FFmpeg("output.mp4", true)
+	if err != nil {
+		fmt.Printf("tl.FFmpeg() error: %v", err)
+		return
+	}
+
+	str, err := ffmpeg.String()
+	if err != nil {
+		fmt.Printf("ffmpeg.String() error: %v", err)
+		return
+	}
+
+	fmt.Println(str)
+
+	// Output:
+	// echo -ne "1\n00:00:00,000 --> 00:00:03,000\nGood Times with Maomi & Mimao" > "op.srt" && echo -ne "1\n00:00:00,000 --> 00:00:05,000\nMido's tickling Mimao and he's enjoying..." > "01.srt" && echo -ne "1\n00:00:00,000 --> 00:00:08,000\nIt's hard to brush Maomi's teeth..." > "03.srt" && echo "y" | ffmpeg \
+	// -i "op.jpg" \
+	// -i "01.MP4" \
+	// -i "02.MOV" \
+	// -i "03.MOV" \
+	// -i "ed.jpg" \
+	// -filter_complex " \
+	// [0:v:0]loop=loop=89:size=1,setpts=N/(30*TB),scale=720:960:force_original_aspect_ratio=decrease,pad=720:960:(ow-iw)/2:(oh-ih)/2,setsar=1:1,fps=30,format=pix_fmts=yuv420p,subtitles=op.srt:force_style='Fontsize=15',fade=t=out:st=2.000:d=1.000[clip_00_v];
+	// aevalsrc=0:c=stereo:s=48000:d=3.000,aresample=48000,aformat=sample_fmts=fltp:sample_rates=48000:channel_layouts=stereo[clip_00_a];
+	// [1:v:0]trim=end=5.000,setpts=PTS-STARTPTS,scale=720:960:force_original_aspect_ratio=decrease,pad=720:960:(ow-iw)/2:(oh-ih)/2,setsar=1:1,fps=30,format=pix_fmts=yuv420p,subtitles=01.srt:force_style='Fontsize=13'[clip_01_v];
+	// [1:a:0]atrim=end=5.000,asetpts=PTS-STARTPTS,aresample=48000,aformat=sample_fmts=fltp:sample_rates=48000:channel_layouts=stereo[clip_01_a];
+	// [2:v:0]trim=end=4.000,setpts=PTS-STARTPTS,scale=720:960:force_original_aspect_ratio=decrease,pad=720:960:(ow-iw)/2:(oh-ih)/2,setsar=1:1,fps=30,format=pix_fmts=yuv420p[clip_02_v];
+	// aevalsrc=0:c=stereo:s=48000:d=4.000,aresample=48000,aformat=sample_fmts=fltp:sample_rates=48000:channel_layouts=stereo[clip_02_a];
+	// [3:v:0]trim=start=1.000:end=9.000,setpts=PTS-STARTPTS,scale=720:960:force_original_aspect_ratio=decrease,pad=720:960:(ow-iw)/2:(oh-ih)/2,setsar=1:1,fps=30,format=pix_fmts=yuv420p,subtitles=03.srt:force_style='Fontsize=13'[clip_03_v];
+	// [3:a:0]atrim=start=1.000:end=9.000,asetpts=PTS-STARTPTS,aresample=48000,aformat=sample_fmts=fltp:sample_rates=48000:channel_layouts=stereo[clip_03_a];
+	// [4:v:0]loop=loop=89:size=1,setpts=N/(30*TB),scale=720:960:force_original_aspect_ratio=decrease,pad=720:960:(ow-iw)/2:(oh-ih)/2,setsar=1:1,fps=30,format=pix_fmts=yuv420p,fade=t=out:st=2.000:d=1.000[clip_04_v];
+	// aevalsrc=0:c=stereo:s=48000:d=3.000,aresample=48000,aformat=sample_fmts=fltp:sample_rates=48000:channel_layouts=stereo[clip_04_a];
+	// [clip_00_v][clip_00_a][clip_01_v][clip_01_a][clip_02_v][clip_02_a][clip_03_v][clip_03_a][clip_04_v][clip_04_a]concat=n=5:v=1:a=1[outv][outa]" \
+	// -map "[outa]" \
+	// -map "[outv]" \
+	// output.mp4 && rm "op.srt" && rm "01.srt" && rm "03.srt"
+}
+
+func ExampleTimeline_EnableProbing() {
+	// 02.MOV has a video stream of 3.2 seconds only.
+	dir, cleanup, err := fakeFFprobe(map[string]string{"02.MOV": "3.200"})
+	if err != nil {
+		fmt.Printf("fakeFFprobe() error: %v", err)
+		return
+	}
+	defer cleanup()
+
+	tl := ffcmd.NewTimeline(ffcmd.Profile{W: 720, H: 960, FPS: 30})
+	tl.EnableProbing(dir)
+
+	// The missing audio is detected by probing.
+	// The silent audio stops at the end of the file as the trimmed video instead of the end time.
+	tl.AddVideoClip(ffcmd.VideoClip{File: "02.MOV", End: "00:00:05"})
+
+	ffmpeg, err := tl.FFmpeg("output.mp4", true)
+	if err != nil {
+		fmt.Printf("tl.FFmpeg() error: %v", err)
+		return
+	}
+
+	str, err := ffmpeg.String()
+	if err != nil {
+		fmt.Printf("ffmpeg.String() error: %v", err)
+		return
+	}
+
+	fmt.Println(str)
+
+	// Output:
+	// echo "y" | ffmpeg \
+	// -i "02.MOV" \
+	// -filter_complex " \
+	// [0:v:0]trim=end=5.000,setpts=PTS-STARTPTS,scale=720:960:force_original_aspect_ratio=decrease,pad=720:960:(ow-iw)/2:(oh-ih)/2,setsar=1:1,fps=30,format=pix_fmts=yuv420p[clip_00_v];
+	// aevalsrc=0:c=stereo:s=48000:d=3.200,aresample=48000,aformat=sample_fmts=fltp:sample_rates=48000:channel_layouts=stereo[clip_00_a];
+	// [clip_00_v][clip_00_a]concat=n=1:v=1:a=1[outv][outa]" \
+	// -map "[outa]" \
+	// -map "[outv]" \
+	// output.mp4
+}
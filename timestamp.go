@@ -5,7 +5,6 @@ import (
 	"math"
 	"regexp"
 	"strconv"
-	"strings"
 )
 
 // Timestamp represents the timestamp for video and SRT file.
@@ -42,16 +41,18 @@ func NewTimestamp(str string) (*Timestamp, error) {
 
 // NewTimestampFromSecond converts the seconds in float to timestamp.
 func NewTimestampFromSecond(second float32) (*Timestamp, error) {
-	integer, frac := math.Modf(float64(second))
-	sec := int(integer)
+	if second < 0 {
+		return nil, fmt.Errorf("negative second")
+	}
 
-	str := fmt.Sprintf("%.3f", frac)
-	str = strings.TrimPrefix(str, "0.")
-	mmm, _ := strconv.Atoi(str)
+	// Round to millisecond.
+	ms := int(math.Round(float64(second) * 1000))
+	sec := ms / 1000
+	mmm := ms % 1000
 
 	hh := sec / 3600
-	mm := sec / 3600 % 60
-	ss := sec % 3600 % 60
+	mm := sec % 3600 / 60
+	ss := sec % 60
 
 	return &Timestamp{hh: hh, mm: mm, ss: ss, mmm: mmm}, nil
 }
@@ -85,3 +86,8 @@ func (ts *Timestamp) StringForSRT() string {
 func (ts *Timestamp) Second() string {
 	return fmt.Sprintf("%d.%03d", ts.hh*3600+ts.mm*60+ts.ss, ts.mmm)
 }
+
+// seconds returns the timestamp in seconds.
+func (ts *Timestamp) seconds() float64 {
+	return float64(ts.hh*3600+ts.mm*60+ts.ss) + float64(ts.mmm)/1000
+}
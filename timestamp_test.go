@@ -44,5 +44,5 @@ func ExampleTimestamp() {
 	// 20:30:40,900 -> String(): 20:30:40.900, StringForSRT(): 20:30:40,900, Second(): 73840.900
 	// 0.000000 -> String(): 00:00:00.000, StringForSRT(): 00:00:00,000, Second(): 0.000
 	// 3.140000 -> String(): 00:00:03.140, StringForSRT(): 00:00:03,140, Second(): 3.140
-	// 3882.459961 -> String(): 01:01:42.460, StringForSRT(): 01:01:42,460, Second(): 3702.460
+	// 3882.459961 -> String(): 01:04:42.460, StringForSRT(): 01:04:42,460, Second(): 3882.460
 }
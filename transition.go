@@ -0,0 +1,198 @@
+package ffcmd
+
+import (
+	"fmt"
+)
+
+// Transition types of ffmpeg's xfade filter.
+// See https://trac.ffmpeg.org/wiki/Xfade for more.
+const (
+	TransitionFade        = "fade"
+	TransitionFadeBlack   = "fadeblack"
+	TransitionFadeWhite   = "fadewhite"
+	TransitionDissolve    = "dissolve"
+	TransitionWipeLeft    = "wipeleft"
+	TransitionWipeRight   = "wiperight"
+	TransitionWipeUp      = "wipeup"
+	TransitionWipeDown    = "wipedown"
+	TransitionSlideLeft   = "slideleft"
+	TransitionSlideRight  = "slideright"
+	TransitionSlideUp     = "slideup"
+	TransitionSlideDown   = "slidedown"
+	TransitionCircleOpen  = "circleopen"
+	TransitionCircleClose = "circleclose"
+)
+
+// Transition represents the transition between 2 clips.
+type Transition struct {
+	// Type is the transition type of xfade filter(e.g. "fade", "wipeleft", "slideleft").
+	Type string
+	// Duration is the duration of the transition in seconds.
+	// If it's 0, the clips are concatenated without transition(hard cut).
+	Duration float32
+}
+
+// transitionClip stores the video / audio filterchain's outputs and the duration of the clip.
+type transitionClip struct {
+	v          *FilterChain
+	vID        int
+	a          *FilterChain
+	aID        int
+	duration   float32
+	transition Transition
+}
+
+// TransitionSequencer sequences clips with transitions by chaining xfade(video) and acrossfade(audio) filters.
+// All video clips should have the same resolution, SAR, frame rate and pixel format which are required by xfade.
+type TransitionSequencer struct {
+	videoOutput string
+	audioOutput string
+	transition  Transition
+	clips       []*transitionClip
+}
+
+// NewTransitionSequencer returns a new transition sequencer.
+// videoOutput, audioOutput: output labels of the sequenced video / audio stream in the "[OUTPUT_LABEL]" format.
+// transition: default transition between clips.
+func NewTransitionSequencer(videoOutput, audioOutput string, transition Transition) *TransitionSequencer {
+	return &TransitionSequencer{videoOutput: videoOutput, audioOutput: audioOutput, transition: transition}
+}
+
+// AddClip adds a clip by the outputs of its video and audio filterchains.
+// The clip uses the default transition from the previous clip.
+// v, vID: video filterchain and the 0-based index of its output.
+// a, aID: audio filterchain and the 0-based index of its output. a may be nil if all clips have no audio.
+// duration: duration of the clip in seconds.
+func (s *TransitionSequencer) AddClip(v *FilterChain, vID int, a *FilterChain, aID int, duration float32) {
+	s.AddClipWithTransition(v, vID, a, aID, duration, s.transition)
+}
+
+// AddClipWithTransition adds a clip with the transition from the previous clip.
+// The transition is ignored for the first clip.
+func (s *TransitionSequencer) AddClipWithTransition(v *FilterChain, vID int, a *FilterChain, aID int, duration float32, transition Transition) {
+	s.clips = append(s.clips, &transitionClip{v: v, vID: vID, a: a, aID: aID, duration: duration, transition: transition})
+}
+
+// Duration returns the duration of the sequenced clips in seconds.
+// Each transition overlaps the previous and the next clip.
+func (s *TransitionSequencer) Duration() float32 {
+	var d float32
+
+	for i, c := range s.clips {
+		d += c.duration
+		if i > 0 {
+			d -= c.transition.Duration
+		}
+	}
+	return d
+}
+
+// FilterChains returns the video and audio filterchains to add to the filtergraph.
+// The last filterchain of video / audio outputs the sequenced stream labeled by videoOutput / audioOutput.
+// It returns nil audio filterchains if none of the clips has audio.
+func (s *TransitionSequencer) FilterChains() ([]*FilterChain, []*FilterChain, error) {
+	l := len(s.clips)
+	if l == 0 {
+		return nil, nil, fmt.Errorf("no clips to sequence")
+	}
+
+	hasAudio := s.clips[0].a != nil
+	for i, c := range s.clips {
+		if c.v == nil {
+			return nil, nil, fmt.Errorf("clip %d has no video", i)
+		}
+
+		if (c.a != nil) != hasAudio {
+			return nil, nil, fmt.Errorf("clip %d: either all clips or none of them should have audio", i)
+		}
+
+		if c.duration <= 0 {
+			return nil, nil, fmt.Errorf("clip %d: invalid duration", i)
+		}
+
+		if i > 0 {
+			d := c.transition.Duration
+			if d < 0 {
+				return nil, nil, fmt.Errorf("clip %d: invalid transition duration", i)
+			}
+
+			if d >= c.duration || d >= s.clips[i-1].duration {
+				return nil, nil, fmt.Errorf("clip %d: transition duration should be less than the durations of adjacent clips", i)
+			}
+
+			if d > 0 && c.transition.Type == "" {
+				return nil, nil, fmt.Errorf("clip %d: empty transition type", i)
+			}
+		}
+	}
+
+	var vfcs, afcs []*FilterChain
+
+	// Only 1 clip, just pass through the streams to the output labels.
+	if l == 1 {
+		c := s.clips[0]
+		vfc := NewFilterChain(s.videoOutput)
+		vfc.AddInputByOutput(c.v, c.vID)
+		vfc.Chain("null")
+		vfcs = append(vfcs, vfc)
+
+		if hasAudio {
+			afc := NewFilterChain(s.audioOutput)
+			afc.AddInputByOutput(c.a, c.aID)
+			afc.Chain("anull")
+			afcs = append(afcs, afc)
+		}
+		return vfcs, afcs, nil
+	}
+
+	prevV, prevVID := s.clips[0].v, s.clips[0].vID
+	prevA, prevAID := s.clips[0].a, s.clips[0].aID
+
+	// Duration of the sequenced clips so far.
+	length := s.clips[0].duration
+
+	for i := 1; i < l; i++ {
+		c := s.clips[i]
+		d := c.transition.Duration
+
+		vOutput := fmt.Sprintf("[%s_%02d]", labelName(s.videoOutput), i)
+		aOutput := fmt.Sprintf("[%s_%02d]", labelName(s.audioOutput), i)
+		if i == l-1 {
+			vOutput = s.videoOutput
+			aOutput = s.audioOutput
+		}
+
+		vfc := NewFilterChain(vOutput)
+		vfc.AddInputByOutput(prevV, prevVID)
+		vfc.AddInputByOutput(c.v, c.vID)
+
+		if d == 0 {
+			vfc.Chain("concat=n=2:v=1:a=0")
+		} else {
+			// The transition starts at "offset" seconds of the sequenced clips so far.
+			xfade := fmt.Sprintf("xfade=transition=%s:duration=%s:offset=%s", c.transition.Type, formatSecond(d), formatSecond(length-d))
+			vfc.Chain(xfade)
+		}
+		vfcs = append(vfcs, vfc)
+		prevV, prevVID = vfc, 0
+
+		if hasAudio {
+			afc := NewFilterChain(aOutput)
+			afc.AddInputByOutput(prevA, prevAID)
+			afc.AddInputByOutput(c.a, c.aID)
+
+			if d == 0 {
+				afc.Chain("concat=n=2:v=0:a=1")
+			} else {
+				acrossfade := fmt.Sprintf("acrossfade=d=%s", formatSecond(d))
+				afc.Chain(acrossfade)
+			}
+			afcs = append(afcs, afc)
+			prevA, prevAID = afc, 0
+		}
+
+		length += c.duration - d
+	}
+
+	return vfcs, afcs, nil
+}
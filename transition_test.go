@@ -0,0 +1,76 @@
+package ffcmd_test
+
+import (
+	"fmt"
+
+	"github.com/northbright/ffcmd"
+)
+
+func ExampleTransitionSequencer() {
+	// Clips with the same resolution, SAR, frame rate and pixel format.
+	files := []string{"01.mp4", "02.mp4", "03.mp4"}
+	durations := []float32{5, 4, 6}
+
+	ffmpeg := ffcmd.New("output.mp4", true)
+
+	// Use 1-second fade as default transition.
+	seq := ffcmd.NewTransitionSequencer("[outv]", "[outa]", ffcmd.Transition{Type: ffcmd.TransitionFade, Duration: 1})
+
+	for i, file := range files {
+		id := ffmpeg.AddInput(file)
+
+		// Filterchains without filters use the input streams as outputs directly.
+		v := ffcmd.NewFilterChain()
+		v.AddInputByID(id, "v", 0)
+
+		a := ffcmd.NewFilterChain()
+		a.AddInputByID(id, "a", 0)
+
+		if i == 2 {
+			// Use a 0.5-second slide for the last clip.
+			seq.AddClipWithTransition(v, 0, a, 0, durations[i], ffcmd.Transition{Type: ffcmd.TransitionSlideLeft, Duration: 0.5})
+		} else {
+			seq.AddClip(v, 0, a, 0, durations[i])
+		}
+	}
+
+	vfcs, afcs, err := seq.FilterChains()
+	if err != nil {
+		fmt.Printf("seq.FilterChains() error: %v", err)
+		return
+	}
+
+	for _, fc := range vfcs {
+		ffmpeg.Chain(fc)
+	}
+
+	for _, fc := range afcs {
+		ffmpeg.Chain(fc)
+	}
+
+	ffmpeg.MapByOutput(vfcs[len(vfcs)-1], 0)
+
+	str, err := ffmpeg.String()
+	if err != nil {
+		fmt.Printf("ffmpeg.String() error: %v", err)
+		return
+	}
+
+	fmt.Printf("duration: %.1f\n", seq.Duration())
+	fmt.Println(str)
+
+	// Output:
+	// duration: 13.5
+	// echo "y" | ffmpeg \
+	// -i "01.mp4" \
+	// -i "02.mp4" \
+	// -i "03.mp4" \
+	// -filter_complex " \
+	// [0:v:0][1:v:0]xfade=transition=fade:duration=1.000:offset=4.000[outv_01];
+	// [outv_01][2:v:0]xfade=transition=slideleft:duration=0.500:offset=7.500[outv];
+	// [0:a:0][1:a:0]acrossfade=d=1.000[outa_01];
+	// [outa_01][2:a:0]acrossfade=d=0.500[outa]" \
+	// -map "[outa]" \
+	// -map "[outv]" \
+	// output.mp4
+}
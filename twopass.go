@@ -0,0 +1,182 @@
+package ffcmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Video codecs supported by two-pass encoding.
+const (
+	CodecX264 = "libx264"
+	CodecX265 = "libx265"
+	CodecVP9  = "libvpx-vp9"
+)
+
+// TwoPass is the command to encode the output of ffmpeg command in two passes by target bitrate.
+// Pass 1 analyzes the video and writes the stats to the passlog files. Pass 2 encodes the output by the stats.
+// It implements Cmd interface.
+type TwoPass struct {
+	ff          *FFmpeg
+	codec       string
+	bitrate     string
+	passLogFile string
+}
+
+// NewTwoPass returns a new two-pass encoding command.
+// ff: ffmpeg command to render the output. Its pre-commands run before pass 1 and post-commands run after pass 2.
+// codec: video codec(CodecX264, CodecX265 or CodecVP9).
+// bitrate: target video bitrate(e.g. "2M", "800k").
+func NewTwoPass(ff *FFmpeg, codec, bitrate string) (*TwoPass, error) {
+	switch codec {
+	case CodecX264, CodecX265, CodecVP9:
+	default:
+		return nil, fmt.Errorf("unsupported codec: %s", codec)
+	}
+
+	if bitrate == "" {
+		return nil, fmt.Errorf("empty bitrate")
+	}
+
+	// Passlog files are created in the working dir and named by the output(e.g. "output_passlog-0.log" for "output.mp4").
+	output := filepath.Base(ff.output)
+	passLogFile := strings.TrimSuffix(output, filepath.Ext(output)) + "_passlog"
+
+	return &TwoPass{ff: ff, codec: codec, bitrate: bitrate, passLogFile: passLogFile}, nil
+}
+
+// PassLogFiles returns the passlog files created by pass 1.
+func (tp *TwoPass) PassLogFiles() []string {
+	switch tp.codec {
+	case CodecX264:
+		return []string{tp.passLogFile + "-0.log", tp.passLogFile + "-0.log.mbtree"}
+	case CodecX265:
+		return []string{tp.passLogFile + ".log", tp.passLogFile + ".log.cutree"}
+	default:
+		return []string{tp.passLogFile + "-0.log"}
+	}
+}
+
+// passOptions returns the output options of the pass.
+func (tp *TwoPass) passOptions(pass int) []string {
+	options := []string{"-c:v", tp.codec, "-b:v", tp.bitrate}
+
+	if tp.codec == CodecX265 {
+		// x265 reads the pass and stats file from its own params.
+		return append(options, "-x265-params", fmt.Sprintf("pass=%d:stats=%s.log", pass, tp.passLogFile))
+	}
+
+	return append(options, "-pass", fmt.Sprintf("%d", pass), "-passlogfile", tp.passLogFile)
+}
+
+// clone returns a copy of ffmpeg command which can be modified without affecting the original one.
+func (ff *FFmpeg) clone() *FFmpeg {
+	c := *ff
+	c.inputs = append([]string{}, ff.inputs...)
+	c.inputOptions = append([][]string{}, ff.inputOptions...)
+	c.outputOptions = append([]string{}, ff.outputOptions...)
+	c.fg = append([]*FilterChain{}, ff.fg...)
+	c.preCmds = append([]Cmd{}, ff.preCmds...)
+	c.postCmds = append([]Cmd{}, ff.postCmds...)
+
+	c.selectedStreams = make(map[string]struct{})
+	for stream := range ff.selectedStreams {
+		c.selectedStreams[stream] = struct{}{}
+	}
+
+	return &c
+}
+
+// Passes returns the ffmpeg commands of pass 1 and pass 2.
+func (tp *TwoPass) Passes() (*FFmpeg, *FFmpeg, error) {
+	// Pass 1 discards the output and keeps the pre-commands only.
+	// Audio is kept to make sure all the labeled outputs of filtergraph are mapped.
+	pass1 := tp.ff.clone()
+	pass1.output = "/dev/null"
+	pass1.overwrite = true
+	pass1.postCmds = nil
+	pass1.AddOutputOptions(tp.passOptions(1)...)
+	pass1.AddOutputOptions("-f", "null")
+
+	// Pass 2 keeps the post-commands only and removes the passlog files.
+	pass2 := tp.ff.clone()
+	pass2.preCmds = nil
+	pass2.AddOutputOptions(tp.passOptions(2)...)
+
+	for _, file := range tp.PassLogFiles() {
+		cmd, err := NewRemoveFileCmd(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("NewRemoveFileCmd() error: %v", err)
+		}
+		pass2.AddPostCmd(cmd)
+	}
+
+	return pass1, pass2, nil
+}
+
+// String returns the command string to run.
+func (tp *TwoPass) String() (string, error) {
+	return tp.string(func(ff *FFmpeg) (string, error) {
+		return ff.String()
+	})
+}
+
+// ResolvedString returns the command string with the pre-commands and post-commands of the ffmpeg command resolved. See FFmpeg.ResolvedString() for more.
+func (tp *TwoPass) ResolvedString(ctx context.Context, dir string) (string, error) {
+	return tp.string(func(ff *FFmpeg) (string, error) {
+		return ff.ResolvedString(ctx, dir)
+	})
+}
+
+// string returns the command string with the strings of passes returned by passString.
+func (tp *TwoPass) string(passString func(*FFmpeg) (string, error)) (string, error) {
+	pass1, pass2, err := tp.Passes()
+	if err != nil {
+		return "", err
+	}
+
+	str1, err := passString(pass1)
+	if err != nil {
+		return "", fmt.Errorf("pass 1 error: %v", err)
+	}
+
+	str2, err := passString(pass2)
+	if err != nil {
+		return "", fmt.Errorf("pass 2 error: %v", err)
+	}
+
+	return fmt.Sprintf("%s && %s", str1, str2), nil
+}
+
+// Preflight checks the problems before running the passes. See FFmpeg.Preflight() for more.
+func (tp *TwoPass) Preflight(dir string) error {
+	return tp.ff.Preflight(dir)
+}
+
+// Validate checks if the filters, codecs and muxers used by both passes are supported. See FFmpeg.Validate() for more.
+func (tp *TwoPass) Validate(caps *Capabilities) error {
+	pass1, pass2, err := tp.Passes()
+	if err != nil {
+		return err
+	}
+
+	if err := pass1.Validate(caps); err != nil {
+		return fmt.Errorf("pass 1: %w", err)
+	}
+
+	if err := pass2.Validate(caps); err != nil {
+		return fmt.Errorf("pass 2: %w", err)
+	}
+
+	return nil
+}
+
+func (tp *TwoPass) Run(dir string, fn ReadOutputFunc) error {
+	str, err := tp.ResolvedString(context.Background(), dir)
+	if err != nil {
+		return fmt.Errorf("tp.ResolvedString() error: %v", err)
+	}
+
+	return RunCmd(dir, str, fn)
+}
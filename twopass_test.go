@@ -0,0 +1,51 @@
+package ffcmd_test
+
+import (
+	"fmt"
+
+	"github.com/northbright/ffcmd"
+)
+
+func ExampleTwoPass() {
+	ff := ffcmd.New("output.mp4", true)
+	ff.AddInput("input.mov")
+
+	v := ffcmd.NewFilterChain("[outv]")
+	v.AddInputByID(0, "v", 0)
+	v.Chain("scale=1280:720")
+	ff.Chain(v)
+
+	ff.MapByID(0, "a", 0)
+	ff.AddOutputOptions("-c:a", "aac", "-b:a", "128k")
+
+	tp, err := ffcmd.NewTwoPass(ff, ffcmd.CodecX264, "2M")
+	if err != nil {
+		fmt.Printf("ffcmd.NewTwoPass() error: %v", err)
+		return
+	}
+
+	str, err := tp.String()
+	if err != nil {
+		fmt.Printf("tp.String() error: %v", err)
+		return
+	}
+
+	fmt.Println(str)
+
+	// Output:
+	// echo "y" | ffmpeg \
+	// -i "input.mov" \
+	// -filter_complex " \
+	// [0:v:0]scale=1280:720[outv]" \
+	// -map "0:a:0" \
+	// -map "[outv]" \
+	// -c:a aac -b:a 128k -c:v libx264 -b:v 2M -pass 1 -passlogfile output_passlog -f null \
+	// /dev/null && echo "y" | ffmpeg \
+	// -i "input.mov" \
+	// -filter_complex " \
+	// [0:v:0]scale=1280:720[outv]" \
+	// -map "0:a:0" \
+	// -map "[outv]" \
+	// -c:a aac -b:a 128k -c:v libx264 -b:v 2M -pass 2 -passlogfile output_passlog \
+	// output.mp4 && rm "output_passlog-0.log" && rm "output_passlog-0.log.mbtree"
+}